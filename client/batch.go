@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/masonJamesWheeler/alpha-vantage-go-wrapper/models"
+)
+
+// BatchOptions configures the optional behavior of the multi-request helpers.
+type BatchOptions struct {
+	// OnProgress, if set, is called after each sub-request completes, successfully or not,
+	// with the number of completed sub-requests and the total. It is called from the calling
+	// goroutine, never concurrently, so it may update a progress bar without locking.
+	OnProgress func(done, total int)
+}
+
+// progress reports done of total sub-requests to the OnProgress callback of opts, if any.
+func progress(opts []BatchOptions, done, total int) {
+	if len(opts) > 0 && opts[0].OnProgress != nil {
+		opts[0].OnProgress(done, total)
+	}
+}
+
+// GetDailyBatch retrieves daily data for each of the given symbols concurrently, using params
+// for everything but the symbol. At most WithBatchConcurrency requests are in flight at once and
+// the client's rate limit, if set, is respected. Canceling ctx aborts all in-flight requests.
+//
+// Every symbol ends up in exactly one of the returned maps. The optional BatchOptions, at most
+// one, can report progress; symbols skipped because ctx was canceled are not reported.
+func (c *Client) GetDailyBatch(ctx context.Context, symbols []string, params models.TimeSeriesParams, opts ...BatchOptions) (map[string]models.TimeSeriesDaily, map[string]error) {
+	type result struct {
+		symbol string
+		data   models.TimeSeriesDaily
+		err    error
+	}
+
+	workers := c.batchConcurrency
+	if workers > len(symbols) {
+		workers = len(symbols)
+	}
+
+	jobs := make(chan string)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range jobs {
+				symbolParams := params
+				symbolParams.Symbol = symbol
+				data, err := c.getDaily(ctx, symbolParams)
+				results <- result{symbol: symbol, data: data, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, symbol := range symbols {
+			select {
+			case jobs <- symbol:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	series := make(map[string]models.TimeSeriesDaily)
+	errs := make(map[string]error)
+	done := 0
+	for r := range results {
+		done++
+		if r.err != nil {
+			errs[r.symbol] = r.err
+		} else {
+			series[r.symbol] = r.data
+		}
+		progress(opts, done, len(symbols))
+	}
+
+	// Symbols that were never dispatched because ctx was canceled
+	for _, symbol := range symbols {
+		if _, ok := series[symbol]; ok {
+			continue
+		}
+		if _, ok := errs[symbol]; !ok {
+			errs[symbol] = ctx.Err()
+		}
+	}
+
+	return series, errs
+}
+
+// GetIntradayRange retrieves the intraday bars of symbol between from and to by issuing one
+// request per calendar month (outputsize=full), respecting the client's rate limit. The bars
+// of all months are merged, de-duplicated by timestamp and returned sorted ascending; the
+// metadata is taken from the most recent month.
+//
+// If a month fails, the bars collected so far are returned together with a wrapped error. With
+// WithLenientParsing, a month with unparsable bars does not fail: its other bars are merged and
+// the skipped entries of every month are returned as a models.ParseErrors. The optional
+// BatchOptions, at most one, can report progress after each month.
+func (c *Client) GetIntradayRange(ctx context.Context, symbol, interval string, from, to time.Time, opts ...BatchOptions) (models.TimeSeriesIntraday, error) {
+	if to.Before(from) {
+		return models.TimeSeriesIntraday{}, fmt.Errorf("invalid range: %s is before %s", to.Format("2006-01"), from.Format("2006-01"))
+	}
+
+	var merged models.TimeSeriesIntraday
+	var parseErrs models.ParseErrors
+
+	months := models.MonthsBetween(from, to)
+	var rangeErr error
+	for i, month := range months {
+		data, err := c.getIntraday(ctx, models.TimeSeriesParams{
+			Symbol:     symbol,
+			Interval:   models.Interval(interval),
+			Month:      month,
+			OutputSize: models.OutputSizeFull,
+		})
+		progress(opts, i+1, len(months))
+		if err != nil {
+			// With lenient parsing the month's bars come with the entries that were skipped
+			var monthErrs models.ParseErrors
+			if !c.acceptPartial(err) || !errors.As(err, &monthErrs) {
+				rangeErr = fmt.Errorf("fetching intraday data for %s in %s: %w", symbol, month, err)
+				break
+			}
+			parseErrs = append(parseErrs, monthErrs...)
+		}
+
+		merged.Merge(data)
+	}
+
+	if rangeErr == nil && len(parseErrs) > 0 {
+		rangeErr = parseErrs
+	}
+	return merged, rangeErr
+}
+
+// GetIndicators retrieves each of the given indicator functions, e.g. "RSI" or "BBANDS", for
+// the symbol of base and joins their outputs on timestamp. One request is issued per function,
+// one after another, so the client's rate limit, if set, is respected. Each function is sent
+// with base as is but for the function name, and checked like the name given to GetIndicator.
+//
+// The inner maps are keyed by output name as returned by the API, e.g. "RSI" or
+// "Real Upper Band". A timestamp covered by only some of the functions carries only their
+// outputs. The first failing function aborts the call.
+func (c *Client) GetIndicators(ctx context.Context, base models.IndicatorParams, functions []string) (map[time.Time]map[string]float64, error) {
+	frame := make(map[time.Time]map[string]float64)
+	for _, function := range functions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		indicator, err := c.getIndicatorByName(ctx, function, base)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching %s: %w", function, err)
+		}
+
+		for _, v := range indicator.IndicatorValues {
+			row, ok := frame[v.Timestamp]
+			if !ok {
+				row = make(map[string]float64, len(v.Values))
+				frame[v.Timestamp] = row
+			}
+			for name, value := range v.Values {
+				row[name] = value
+			}
+		}
+	}
+
+	return frame, nil
+}
+
+// IndicatorSpec names one indicator function of GetIndicatorSet and its parameters.
+type IndicatorSpec struct {
+	Function string
+	Params   models.IndicatorParams
+}
+
+// GetIndicatorSet retrieves each spec's indicator and returns the full responses keyed by
+// function name, unlike GetIndicators which joins them on timestamp. One request is issued per
+// spec, one after another, so the client's rate limit, if set, is respected.
+//
+// A failing spec does not abort the others: the responses that succeeded are returned together
+// with an error joining the failures, each naming its function. Function names are checked like
+// the name given to GetIndicator and must be unique; the responses are keyed by the upper-case
+// name, e.g. "RSI" for "rsi".
+func (c *Client) GetIndicatorSet(ctx context.Context, specs []IndicatorSpec) (map[string]*models.IndicatorResponse, error) {
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		function := normalizeIndicatorFunction(spec.Function)
+		if seen[function] {
+			return nil, fmt.Errorf("duplicate indicator function %q", function)
+		}
+		seen[function] = true
+	}
+
+	responses := make(map[string]*models.IndicatorResponse, len(specs))
+	var errs []error
+	for _, spec := range specs {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		function := normalizeIndicatorFunction(spec.Function)
+		indicator, err := c.getIndicatorByName(ctx, function, spec.Params)
+		if indicator != nil {
+			responses[function] = indicator
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error fetching %s: %w", function, err))
+		}
+	}
+
+	return responses, errors.Join(errs...)
+}
@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/masonJamesWheeler/alpha-vantage-go-wrapper/models"
+)
+
+// intradayMonthHandler answers each monthly intraday request with two bars of that month, the
+// later one with a malformed volume in month bad, and with an error message in month failing.
+func intradayMonthHandler(bad, failing string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		month := r.URL.Query().Get("month")
+		if month == failing {
+			w.Write([]byte(`{"Error Message": "Invalid API call."}`))
+			return
+		}
+		volume := "100"
+		if month == bad {
+			volume = "n/a"
+		}
+		// The later bar comes first, as in the API's newest-first responses
+		fmt.Fprintf(w, `{"Meta Data": {"2. Symbol": "IBM", "3. Last Refreshed": "%[1]s-02 16:00:00"}, "Time Series (5min)": {
+			"%[1]s-02 16:00:00": {"1. open": "2", "2. high": "2", "3. low": "2", "4. close": "2", "5. volume": "%[2]s"},
+			"%[1]s-01 09:30:00": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "100"}}}`, month, volume)
+	}
+}
+
+func TestGetIntradayRange(t *testing.T) {
+	c := newTestClient(t, intradayMonthHandler("", ""))
+	from := time.Date(2023, 11, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	series, err := c.GetIntradayRange(context.Background(), "IBM", "5min", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series.TimeSeries) != 6 {
+		t.Fatalf("got %d bars, want 6", len(series.TimeSeries))
+	}
+	for i := 1; i < len(series.TimeSeries); i++ {
+		if !series.TimeSeries[i-1].Timestamp.Before(series.TimeSeries[i].Timestamp) {
+			t.Fatalf("bars not ascending at %d: %v", i, series.TimeSeries)
+		}
+	}
+	if series.MetaData.LastRefreshed != "2024-01-02 16:00:00" {
+		t.Errorf("metadata of %q, want the most recent month", series.MetaData.LastRefreshed)
+	}
+}
+
+func TestGetIntradayRangeLenient(t *testing.T) {
+	c := newTestClient(t, intradayMonthHandler("2023-12", ""), WithLenientParsing())
+	from := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	series, err := c.GetIntradayRange(context.Background(), "IBM", "5min", from, to)
+	var parseErrs models.ParseErrors
+	if !errors.As(err, &parseErrs) || len(parseErrs) != 1 {
+		t.Fatalf("err = %v, want ParseErrors with one entry", err)
+	}
+	// The bad month's other bar is kept and the months after it are still fetched
+	if len(series.TimeSeries) != 5 {
+		t.Errorf("got %d bars, want 5", len(series.TimeSeries))
+	}
+}
+
+func TestGetIntradayRangeStrict(t *testing.T) {
+	c := newTestClient(t, intradayMonthHandler("2023-12", ""))
+	from := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	series, err := c.GetIntradayRange(context.Background(), "IBM", "5min", from, to)
+	if err == nil {
+		t.Fatal("expected an error for the malformed month")
+	}
+	if len(series.TimeSeries) != 2 {
+		t.Errorf("got %d bars, want the 2 of the month before", len(series.TimeSeries))
+	}
+}
+
+func TestGetIntradayRangeFailingMonth(t *testing.T) {
+	c := newTestClient(t, intradayMonthHandler("", "2023-12"), WithLenientParsing())
+	from := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	series, err := c.GetIntradayRange(context.Background(), "IBM", "5min", from, to)
+	var apiErr *models.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *models.APIError", err)
+	}
+	if len(series.TimeSeries) != 2 {
+		t.Errorf("got %d bars, want the 2 of the month before", len(series.TimeSeries))
+	}
+}
+
+// indicatorHandler answers each indicator function with one value at each of the timestamps,
+// under an output named after the function, and any other function with an error message.
+func indicatorHandler(timestamps ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		function := r.URL.Query().Get("function")
+		if function == "CCI" {
+			w.Write([]byte(`{"Error Message": "Invalid API call."}`))
+			return
+		}
+		values := make([]string, len(timestamps))
+		for i, timestamp := range timestamps {
+			values[i] = fmt.Sprintf(`"%s": {"%s": "%d"}`, timestamp, function, i+1)
+		}
+		fmt.Fprintf(w, `{"Meta Data": {"1: Symbol": "IBM"}, "Technical Analysis: %s": {%s}}`, function, strings.Join(values, ", "))
+	}
+}
+
+func TestGetIndicators(t *testing.T) {
+	c := newTestClient(t, indicatorHandler("2023-09-07", "2023-09-08"))
+
+	frame, err := c.GetIndicators(context.Background(), models.NewIndicatorParams("IBM", "daily", 14, "close"), []string{"rsi", " SMA "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frame) != 2 {
+		t.Fatalf("got %d timestamps, want 2", len(frame))
+	}
+	row := frame[time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC)]
+	if row["RSI"] != 2 || row["SMA"] != 2 {
+		t.Errorf("unexpected row %v", row)
+	}
+}
+
+func TestGetIndicatorsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  string
+		functions []string
+	}{
+		{"unknown function", "daily", []string{"RSI", "NOPE"}},
+		{"daily VWAP", "daily", []string{"VWAP"}},
+		{"failing function", "daily", []string{"RSI", "CCI"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, indicatorHandler("2023-09-08"))
+			frame, err := c.GetIndicators(context.Background(), models.NewIndicatorParams("IBM", tt.interval, 14, "close"), tt.functions)
+			if err == nil || frame != nil {
+				t.Fatalf("got %v, %v, want an error and no frame", frame, err)
+			}
+			if !strings.Contains(err.Error(), tt.functions[len(tt.functions)-1]) {
+				t.Errorf("error %q does not name the failing function", err)
+			}
+		})
+	}
+}
+
+func TestGetIndicatorSet(t *testing.T) {
+	c := newTestClient(t, indicatorHandler("2023-09-08 16:00", "2023-09-08 16:05"))
+	intraday := models.NewIndicatorParams("IBM", "5min", 14, "close")
+	daily := models.NewIndicatorParams("IBM", "daily", 14, "close")
+
+	responses, err := c.GetIndicatorSet(context.Background(), []IndicatorSpec{
+		{Function: "rsi", Params: intraday},
+		{Function: "sma", Params: intraday},
+		{Function: "vwap ", Params: daily},
+		{Function: "CCI", Params: intraday},
+		{Function: "NOPE", Params: intraday},
+	})
+	// Each failing spec is reported, by name, without aborting the others
+	if err == nil {
+		t.Fatal("expected the failing specs to be reported")
+	}
+	for _, function := range []string{"VWAP", "CCI", "NOPE"} {
+		if !strings.Contains(err.Error(), function) {
+			t.Errorf("error %q does not name %s", err, function)
+		}
+	}
+	if len(responses) != 2 || responses["RSI"] == nil || responses["SMA"] == nil {
+		t.Fatalf("got responses for %v, want RSI and SMA", responses)
+	}
+	if len(responses["RSI"].IndicatorValues) != 2 {
+		t.Errorf("got %d RSI values, want 2", len(responses["RSI"].IndicatorValues))
+	}
+}
+
+func TestGetIndicatorSetDuplicate(t *testing.T) {
+	recorder := &queryRecorder{body: rsiBody}
+	c := newTestClient(t, recorder.ServeHTTP)
+	params := models.NewIndicatorParams("IBM", "5min", 14, "close")
+
+	_, err := c.GetIndicatorSet(context.Background(), []IndicatorSpec{{Function: "RSI", Params: params}, {Function: "rsi", Params: params}})
+	if err == nil {
+		t.Fatal("expected an error for a function given twice")
+	}
+	if recorder.Query() != nil {
+		t.Errorf("request sent despite the duplicate: %v", recorder.Query())
+	}
+}
+
+// dailyBatchHandler answers each daily request with the daily fixture, and requests for the
+// failing symbol with an error message.
+func dailyBatchHandler(t *testing.T, failing string) http.HandlerFunc {
+	body := readFixture(t, "daily.json")
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("symbol") == failing {
+			w.Write([]byte(`{"Error Message": "Invalid API call."}`))
+			return
+		}
+		w.Write(body)
+	}
+}
+
+// TestGetDailyBatch runs a batch with one failing symbol across several workers. Run it with -race.
+func TestGetDailyBatch(t *testing.T) {
+	c := newTestClient(t, dailyBatchHandler(t, "BAD"), WithBatchConcurrency(3))
+	symbols := []string{"IBM", "AAPL", "BAD", "MSFT", "TSLA"}
+
+	series, errs := c.GetDailyBatch(context.Background(), symbols, models.TimeSeriesParams{})
+	if len(series) != 4 || len(errs) != 1 {
+		t.Fatalf("got %d series and %d errors, want 4 and 1", len(series), len(errs))
+	}
+	for _, symbol := range []string{"IBM", "AAPL", "MSFT", "TSLA"} {
+		if len(series[symbol].TimeSeries) != 3 {
+			t.Errorf("%s: got %d bars, want 3", symbol, len(series[symbol].TimeSeries))
+		}
+	}
+	var apiErr *models.APIError
+	if !errors.As(errs["BAD"], &apiErr) {
+		t.Errorf("BAD: err = %v, want *models.APIError", errs["BAD"])
+	}
+}
+
+func TestGetDailyBatchProgress(t *testing.T) {
+	c := newTestClient(t, dailyBatchHandler(t, "BAD"), WithBatchConcurrency(3))
+	symbols := []string{"IBM", "AAPL", "BAD", "MSFT", "TSLA"}
+
+	// OnProgress is never called concurrently, so the calls need no locking; -race checks that
+	var calls [][2]int
+	opts := BatchOptions{OnProgress: func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	}}
+	c.GetDailyBatch(context.Background(), symbols, models.TimeSeriesParams{}, opts)
+
+	// The failing symbol is reported like the others
+	if len(calls) != len(symbols) {
+		t.Fatalf("OnProgress called %d times, want %d", len(calls), len(symbols))
+	}
+	for i, call := range calls {
+		if call != [2]int{i + 1, len(symbols)} {
+			t.Errorf("call %d = %v, want [%d %d]", i, call, i+1, len(symbols))
+		}
+	}
+}
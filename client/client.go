@@ -16,203 +16,1198 @@
 package client
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 	"github.com/masonJamesWheeler/alpha-vantage-go-wrapper/models"
 	"encoding/json"
 )
 
 const alphaVantageURL = "https://www.alphavantage.co/query"
 
+// defaultBatchConcurrency is the number of concurrent requests issued by the batch helpers.
+const defaultBatchConcurrency = 5
+
 // Client represents the Alpha Vantage client
+//
+// A Client is safe for concurrent use by multiple goroutines once created. Its mutable state,
+// the key ring of WithAPIKeys, the limiter of WithRateLimit and the response captured by
+// WithRawCapture, is guarded internally and shared by all requests; everything else is only
+// set by the options passed to NewClient.
 type Client struct {
-	apiKey string
+	apiKey           string
+	baseURL          string
+	httpClient       *http.Client
+	ownHTTPClient    *http.Client // the client created by NewClient, the only one Close closes
+	limiter          *rateLimiter
+	keys             *keyRing
+	authHeader       string
+	outputSize       models.OutputSize
+	location         *time.Location
+	batchConcurrency int
+	lenient          bool
+	errorOnEmpty     bool
+	exchangeSuffix   string
+	raw              *rawCapture
+}
+
+// Option configures optional behavior of the Client.
+type Option func(*Client)
+
+// WithBaseURL overrides the Alpha Vantage query URL, e.g. to target an httptest.Server or a proxy.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the http.Client used for requests, e.g. to configure timeouts or a proxy.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithRateLimit spaces out requests so that at most requestsPerMinute are issued per minute.
+// The limit is shared by every request of the Client, including concurrent batch requests.
+func WithRateLimit(requestsPerMinute int) Option {
+	return func(c *Client) {
+		if requestsPerMinute > 0 {
+			c.limiter = newRateLimiter(time.Minute / time.Duration(requestsPerMinute))
+		}
+	}
+}
+
+// WithBatchConcurrency sets the maximum number of concurrent requests issued by the batch helpers.
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.batchConcurrency = n
+		}
+	}
+}
+
+// WithAPIKeys makes the client cycle through keys round-robin, one key per request, instead
+// of using the key passed to NewClient. When a key is answered with a rate-limit note the
+// request is retried with the next key until every key has been tried once.
+func WithAPIKeys(keys []string) Option {
+	return func(c *Client) {
+		if len(keys) > 0 {
+			c.keys = newKeyRing(keys)
+		}
+	}
+}
+
+// WithHeaderAuth sends the API key in the named request header instead of the apikey query
+// parameter, so that it does not appear in logged URLs. Alpha Vantage itself only reads the
+// query parameter; this is meant for proxies that move the header back into the query.
+func WithHeaderAuth(headerName string) Option {
+	return func(c *Client) {
+		c.authHeader = headerName
+	}
+}
+
+// WithDefaultOutputSize sets the output size sent with every series request that does not set
+// its own. Without it such requests omit the parameter and the API returns the compact size.
+func WithDefaultOutputSize(size models.OutputSize) Option {
+	return func(c *Client) {
+		c.outputSize = size
+	}
+}
+
+// WithLocation makes the intraday endpoints return their timestamps in loc. The wall-clock
+// times of the API are first localized to the zone named in the metadata, so the instants
+// are correct, then converted to loc. Without it the wall-clock times are returned as UTC.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Client) {
+		c.location = loc
+	}
+}
+
+// WithLenientParsing makes the series and indicator endpoints return the entries that could be
+// parsed when some could not, together with a models.ParseErrors error listing the failures.
+// The partial result is otherwise treated like a complete one: with WithErrorOnEmpty, a result
+// left with no entries still fails with models.ErrEmptySeries. Without it such a response
+// fails as a whole and the zero value is returned.
+func WithLenientParsing() Option {
+	return func(c *Client) {
+		c.lenient = true
+	}
+}
+
+// WithRawCapture makes the client keep a copy of the last response body it received, which
+// LastRaw returns, so that a result that looks wrong can be checked against what the API sent
+// without requesting it again. Messages sent in place of data are captured as well.
+func WithRawCapture() Option {
+	return func(c *Client) {
+		c.raw = &rawCapture{}
+	}
+}
+
+// WithErrorOnEmpty makes the time series, indicator and crypto endpoints fail with
+// models.ErrNoData when a response has its data section but it parses to no entries at all;
+// the error is more precisely models.ErrEmptySeries, which wraps ErrNoData. By default such a
+// response, e.g. for a newly listed symbol, is returned as an empty series. A response without
+// the section fails with models.ErrNoData either way.
+func WithErrorOnEmpty(enabled bool) Option {
+	return func(c *Client) {
+		c.errorOnEmpty = enabled
+	}
 }
 
 // NewClient creates a new Alpha Vantage client
-func NewClient(apiKey string) *Client {
-	return &Client{apiKey: apiKey}
+func NewClient(apiKey string, opts ...Option) *Client {
+	httpClient := newHTTPClient()
+	c := &Client{
+		apiKey:           apiKey,
+		baseURL:          alphaVantageURL,
+		httpClient:       httpClient,
+		ownHTTPClient:    httpClient,
+		batchConcurrency: defaultBatchConcurrency,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// newHTTPClient returns the http.Client used without WithHTTPClient. It has a transport of its
+// own, configured like http.DefaultTransport, so that Close does not affect other users of it.
+func newHTTPClient() *http.Client {
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		return &http.Client{Transport: transport.Clone()}
+	}
+	return &http.Client{}
+}
+
+// Close closes the idle keep-alive connections of the HTTP client created by NewClient. In-flight
+// requests are not interrupted and the client stays usable afterwards. An http.Client passed to
+// WithHTTPClient belongs to the caller and is left alone, so Close is then a no-op.
+func (c *Client) Close() {
+	if c.httpClient == c.ownHTTPClient {
+		c.httpClient.CloseIdleConnections()
+	}
+}
+
+// LastRaw returns a copy of the body of the last response received, or nil without
+// WithRawCapture or before the first response. With concurrent requests, such as those of the
+// batch helpers, it is the body of whichever response arrived last.
+func (c *Client) LastRaw() []byte {
+	if c.raw == nil {
+		return nil
+	}
+	return c.raw.Load()
+}
+
+// acceptPartial reports whether the data decoded alongside err should be returned, which is
+// the case when lenient parsing is enabled and err only reports entries that were skipped.
+func (c *Client) acceptPartial(err error) bool {
+	var parseErrs models.ParseErrors
+	return c.lenient && errors.As(err, &parseErrs)
+}
+
+// checkEmpty returns models.ErrEmptySeries, which wraps models.ErrNoData, for a response of n entries of the given kind when
+// n is zero and WithErrorOnEmpty is enabled.
+func (c *Client) checkEmpty(n int, kind string) error {
+	if c.errorOnEmpty && n == 0 {
+		return fmt.Errorf("%w: no entries in the %s", models.ErrEmptySeries, kind)
+	}
+	return nil
+}
+
+// WithExchangeSuffix composes every equity symbol with the exchange suffix exch, as done by
+// models.SymbolWithExchange, so that bare tickers like "TSCO" are sent as "TSCO.LON". Symbols
+// that already carry a suffix are sent unchanged, as are the symbols of the crypto endpoints.
+// Alpha Vantage covers international listings unevenly, so some endpoints may still return
+// no data for a suffixed symbol.
+func WithExchangeSuffix(exch string) Option {
+	return func(c *Client) {
+		c.exchangeSuffix = exch
+	}
+}
+
+// do issues a GET request with the given query parameters and returns the response body.
+// It waits for the rate limiter, if any, and aborts when ctx is canceled. With WithAPIKeys
+// the apikey parameter is replaced by the next key of the ring, moving on to the following
+// key whenever the response is a rate-limit note. The symbol parameter is normalized and
+// validated first, so " aapl " is sent as "AAPL" and an empty symbol fails without a request.
+// A message sent in place of data is returned as a *models.APIError or *models.RateLimitError
+// naming the function and the query, with the API key redacted.
+func (c *Client) do(ctx context.Context, queryParams url.Values) ([]byte, error) {
+	suffix := c.exchangeSuffix
+	if isCryptoFunction(queryParams.Get("function")) {
+		suffix = ""
+	}
+	params, err := normalizeSymbols(queryParams, suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	var apiErr error
+	if c.keys == nil {
+		body, err = c.get(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		apiErr = peekError(body)
+	} else {
+		for _, key := range c.keys.Next() {
+			params.Set("apikey", key)
+
+			body, err = c.get(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			apiErr = peekError(body)
+
+			var rateErr *models.RateLimitError
+			if !errors.As(apiErr, &rateErr) {
+				break
+			}
+		}
+	}
+
+	if c.raw != nil {
+		c.raw.Store(body)
+	}
+
+	if apiErr != nil {
+		return nil, withRequest(apiErr, params)
+	}
+	return body, nil
+}
+
+// get performs a single rate-limited GET request.
+func (c *Client) get(ctx context.Context, queryParams url.Values) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	apiKey := ""
+	if c.authHeader != "" {
+		apiKey = queryParams.Get("apikey")
+		queryParams = cloneValues(queryParams)
+		queryParams.Del("apikey")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"?"+queryParams.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.authHeader != "" {
+		req.Header.Set(c.authHeader, apiKey)
+	}
+	// Setting the header by hand disables the transparent decompression of http.Transport,
+	// so that it is handled below for any http.Client
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body := io.Reader(resp.Body)
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkHTML(resp.Header.Get("Content-Type"), data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// maxSnippetLength is the number of bytes of an unexpected body quoted in errors.
+const maxSnippetLength = 200
+
+// checkHTML returns models.ErrNonJSONResponse, with the content type and the start of the body,
+// if the response is an HTML page rather than the JSON or CSV sent by the API.
+func checkHTML(contentType string, data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if !strings.Contains(strings.ToLower(contentType), "text/html") && !bytes.HasPrefix(trimmed, []byte("<")) {
+		return nil
+	}
+
+	snippet := trimmed
+	if len(snippet) > maxSnippetLength {
+		snippet = snippet[:maxSnippetLength]
+	}
+	return fmt.Errorf("%w: content type %q, body %q", models.ErrNonJSONResponse, contentType, snippet)
+}
+
+// addOutputSize adds the outputsize parameter, falling back to the client's default when size
+// is empty. Without either the parameter is omitted.
+func (c *Client) addOutputSize(queryParams url.Values, size models.OutputSize) {
+	if size == "" {
+		size = c.outputSize
+	}
+	if size != "" {
+		queryParams.Add("outputsize", string(size))
+	}
+}
+
+// normalizeSymbols returns a copy of queryParams with every symbol, including each entry of
+// a comma-separated list, normalized by models.NormalizeSymbol and checked by
+// models.ValidateSymbol. A non-empty suffix is composed with each symbol by
+// models.SymbolWithExchange.
+func normalizeSymbols(queryParams url.Values, suffix string) (url.Values, error) {
+	params := cloneValues(queryParams)
+	for i, value := range params["symbol"] {
+		symbols := strings.Split(value, ",")
+		for j, symbol := range symbols {
+			symbols[j] = models.SymbolWithExchange(symbol, suffix)
+			if err := models.ValidateSymbol(symbols[j]); err != nil {
+				return nil, err
+			}
+		}
+		params["symbol"][i] = strings.Join(symbols, ",")
+	}
+	return params, nil
+}
+
+// isCryptoFunction reports whether function takes a digital currency as its symbol.
+func isCryptoFunction(function string) bool {
+	return strings.HasPrefix(function, "DIGITAL_CURRENCY_") || strings.HasPrefix(function, "CRYPTO_")
+}
+
+// cloneValues returns a copy of values that can be modified without affecting the original.
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for key, v := range values {
+		clone[key] = append([]string(nil), v...)
+	}
+	return clone
+}
+
+// addDataType adds the datatype parameter unless dataType is empty, in which case the API
+// responds with JSON.
+func addDataType(queryParams url.Values, dataType models.DataType) {
+	if dataType != "" {
+		queryParams.Add("datatype", string(dataType))
+	}
+}
+
+// timeSeriesSections maps each time series function to the prefix of its data section.
+var timeSeriesSections = map[string]string{
+	"TIME_SERIES_INTRADAY":         "Time Series (",
+	"TIME_SERIES_DAILY":            "Time Series (Daily)",
+	"TIME_SERIES_DAILY_ADJUSTED":   "Time Series (Daily Adjusted)",
+	"TIME_SERIES_WEEKLY":           "Weekly Time Series",
+	"TIME_SERIES_WEEKLY_ADJUSTED":  "Weekly Adjusted Time Series",
+	"TIME_SERIES_MONTHLY":          "Monthly Time Series",
+	"TIME_SERIES_MONTHLY_ADJUSTED": "Monthly Adjusted Time Series",
+	"GLOBAL_QUOTE":                 "Global Quote",
+}
+
+// csvTimeSeriesFunctions are the functions of getTimeSeriesData whose getter parses a CSV body.
+// The series getters decode JSON only; their results can be written as CSV with WriteCSV.
+var csvTimeSeriesFunctions = map[string]bool{"GLOBAL_QUOTE": true}
+
+// requireSection returns models.ErrNoData unless the JSON object in data has a top-level key
+// starting with prefix. Messages sent in place of data have already been turned into errors by do.
+func requireSection(data []byte, prefix string) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	for key := range raw {
+		if strings.HasPrefix(key, prefix) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: missing %q section", models.ErrNoData, prefix)
+}
+
+// apiMessageKeys are the top-level keys of the messages Alpha Vantage sends, with a 200 status,
+// in place of data.
+var apiMessageKeys = map[string]bool{"Note": true, "Error Message": true, "Information": true}
+
+// peekError returns the error described by data if it is one of the messages Alpha Vantage
+// sends in place of data: an object holding nothing but "Note", "Error Message" or
+// "Information" keys. Anything else, including a response that carries such a key next to
+// its data and bodies that are not JSON objects such as CSV, yields nil. Rate-limit notes
+// yield a *models.RateLimitError and other messages a *models.APIError; do fills in the
+// request they answer.
+func peekError(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || len(raw) == 0 {
+		return nil
+	}
+	for key := range raw {
+		if !apiMessageKeys[key] {
+			return nil
+		}
+	}
+
+	var msg string
+	if json.Unmarshal(raw["Error Message"], &msg) == nil {
+		return &models.APIError{Message: msg, Err: models.ErrNoData}
+	}
+	if json.Unmarshal(raw["Information"], &msg) == nil {
+		if isRateLimitMessage(msg) {
+			return &models.RateLimitError{Message: msg}
+		}
+		// Premium functions answer free keys with an explanatory "Information" message
+		if isPremiumMessage(msg) {
+			return &models.APIError{Message: msg, Err: models.ErrPremiumEndpoint}
+		}
+		return &models.APIError{Message: msg, Err: models.ErrNoData}
+	}
+	if json.Unmarshal(raw["Note"], &msg) == nil {
+		return &models.RateLimitError{Message: msg}
+	}
+	return nil
+}
+
+// withRequest attaches the function and the redacted query of queryParams to the
+// *models.APIError or *models.RateLimitError err.
+func withRequest(err error, queryParams url.Values) error {
+	function := queryParams.Get("function")
+	query := models.RedactQuery(queryParams)
+
+	var apiErr *models.APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Function, apiErr.Query = function, query
+	}
+	var rateErr *models.RateLimitError
+	if errors.As(err, &rateErr) {
+		rateErr.Function, rateErr.Query = function, query
+	}
+	return err
+}
+
+// isPremiumMessage reports whether info is the message sent in place of the data of a
+// premium function.
+func isPremiumMessage(info string) bool {
+	return strings.Contains(strings.ToLower(info), "premium endpoint")
 }
 
-// getTimeSeriesData retrieves time series data based on the provided parameters.
-func (c *Client) getTimeSeriesData(function string, params models.TimeSeriesParams) ([]byte, error) {
+// getTimeSeriesData retrieves time series data based on the provided parameters. DataTypeCSV
+// is rejected, without a request, for the functions not in csvTimeSeriesFunctions.
+func (c *Client) getTimeSeriesData(ctx context.Context, function string, params models.TimeSeriesParams) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.DataType == models.DataTypeCSV && !csvTimeSeriesFunctions[function] {
+		return nil, fmt.Errorf("datatype csv is not supported for %s: the series is decoded from JSON; use its WriteCSV method for CSV output", function)
+	}
+
 	queryParams := url.Values{}
 	queryParams.Add("function", function)
 	queryParams.Add("symbol", params.Symbol)
-	queryParams.Add("interval", params.Interval)
+	queryParams.Add("interval", string(params.Interval))
+
+	if monthStr, ok := params.Month.(string); ok {
+		queryParams.Add("month", monthStr)
+	} else if monthPtr, ok := params.Month.(*string); ok {
+		queryParams.Add("month", *monthPtr)
+	}
+
+	var outputSize models.OutputSize
+	switch size := params.OutputSize.(type) {
+	case models.OutputSize:
+		outputSize = size
+	case *models.OutputSize:
+		outputSize = *size
+	case string:
+		outputSize = models.OutputSize(size)
+	case *string:
+		outputSize = models.OutputSize(*size)
+	}
+	c.addOutputSize(queryParams, outputSize)
+
+	addDataType(queryParams, params.DataType)
+
+	if params.Adjusted != nil {
+		queryParams.Add("adjusted", strconv.FormatBool(*params.Adjusted))
+	}
+
+	if params.ExtendedHours != nil {
+		queryParams.Add("extended_hours", strconv.FormatBool(*params.ExtendedHours))
+	}
+
+	if params.Entitlement != "" {
+		queryParams.Add("entitlement", params.Entitlement)
+	}
+
+	queryParams.Add("apikey", c.apiKey)
+
+	data, err := c.do(ctx, queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// CSV bodies have no sections to check; errors, sent as JSON, are caught by do
+	if params.DataType == models.DataTypeCSV {
+		return data, nil
+	}
+
+	if err := requireSection(data, timeSeriesSections[function]); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetIndicatorData retrieves indicator data based on the provided parameters.
+func (c *Client) GetIndicatorData(params models.IndicatorParams) ([]byte, error) {
+	return c.getIndicatorData(context.Background(), params)
+}
+
+// withoutSeriesType lists the indicator functions computed from the whole bar, or its volume,
+// rather than from one price, which take no series_type parameter.
+var withoutSeriesType = map[string]bool{
+	"VWAP": true, "OBV": true, "AD": true, "ADOSC": true, "STOCH": true, "STOCHF": true,
+	"WILLR": true, "ADX": true, "ADXR": true, "AROON": true, "AROONOSC": true, "BOP": true,
+	"CCI": true, "MFI": true, "ULTOSC": true, "DX": true, "MINUS_DI": true, "PLUS_DI": true,
+	"MINUS_DM": true, "PLUS_DM": true, "MIDPRICE": true, "SAR": true, "TRANGE": true,
+	"ATR": true, "NATR": true,
+}
+
+// getIndicatorData retrieves indicator data based on the provided parameters.
+func (c *Client) getIndicatorData(ctx context.Context, params models.IndicatorParams) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	queryParams := url.Values{}
+	queryParams.Add("function", params.Function)
+	queryParams.Add("symbol", params.Symbol)
+	queryParams.Add("interval", string(params.Interval))
+
+	// VWAP is computed from the intraday bars themselves and takes neither parameter
+	if params.Function != "VWAP" {
+		queryParams.Add("time_period", fmt.Sprintf("%d", params.TimePeriod))
+	}
+
+	if !withoutSeriesType[params.Function] {
+		seriesType := params.SeriesType
+		if seriesType == "" {
+			seriesType = models.SeriesTypeClose
+		}
+		queryParams.Add("series_type", string(seriesType))
+	}
+
+	addDataType(queryParams, params.DataType)
+
+	if params.Month != "" {
+		queryParams.Add("month", params.Month)
+	}
+
+	c.addOutputSize(queryParams, params.OutputSize)
+
+	if params.Entitlement != "" {
+		queryParams.Add("entitlement", params.Entitlement)
+	}
+
+	if params.FastPeriod != 0 {
+		queryParams.Add("fastperiod", strconv.Itoa(params.FastPeriod))
+	}
+
+	if params.SlowPeriod != 0 {
+		queryParams.Add("slowperiod", strconv.Itoa(params.SlowPeriod))
+	}
+
+	if params.SignalPeriod != 0 {
+		queryParams.Add("signalperiod", strconv.Itoa(params.SignalPeriod))
+	}
+
+	if params.FastLimit != 0 {
+		queryParams.Add("fastlimit", strconv.FormatFloat(params.FastLimit, 'f', -1, 64))
+	}
+
+	if params.SlowLimit != 0 {
+		queryParams.Add("slowlimit", strconv.FormatFloat(params.SlowLimit, 'f', -1, 64))
+	}
+
+	if params.FastKPeriod != 0 {
+		queryParams.Add("fastkperiod", strconv.Itoa(params.FastKPeriod))
+	}
+
+	if params.SlowKPeriod != 0 {
+		queryParams.Add("slowkperiod", strconv.Itoa(params.SlowKPeriod))
+	}
+
+	if params.SlowDPeriod != 0 {
+		queryParams.Add("slowdperiod", strconv.Itoa(params.SlowDPeriod))
+	}
+
+	if params.SlowKMAType != 0 {
+		queryParams.Add("slowkmatype", strconv.Itoa(params.SlowKMAType))
+	}
+
+	if params.SlowDMAType != 0 {
+		queryParams.Add("slowdmatype", strconv.Itoa(params.SlowDMAType))
+	}
+
+	if params.FastDPeriod != 0 {
+		queryParams.Add("fastdperiod", strconv.Itoa(params.FastDPeriod))
+	}
+
+	if params.FastDMAType != 0 {
+		queryParams.Add("fastdmatype", strconv.Itoa(params.FastDMAType))
+	}
+
+	queryParams.Add("apikey", c.apiKey)
+
+	return c.do(ctx, queryParams)
+}
+
+
+func (c *Client) getIndicator(ctx context.Context, indicatorName string, params models.IndicatorParams) (*models.IndicatorResponse, error) {
+	// Add the function name to the params
+	params.Function = indicatorName
+	// Fetch the data using HTTP, similar to before.
+	data, err := c.getIndicatorData(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.DataType == models.DataTypeCSV {
+		var indicatorResponse models.IndicatorResponse
+		if err := models.ParseIndicatorCSV(&indicatorResponse, data, indicatorName); err != nil {
+			return nil, err
+		}
+		indicatorResponse.MetaData.Symbol = params.Symbol
+		indicatorResponse.MetaData.Interval = string(params.Interval)
+		if err := c.checkEmpty(len(indicatorResponse.IndicatorValues), indicatorName+" values"); err != nil {
+			return nil, err
+		}
+		return &indicatorResponse, nil
+	}
+
+	if err := requireSection(data, "Technical Analysis: "+indicatorName); err != nil {
+		return nil, err
+	}
+
+	var indicatorResponse models.IndicatorResponse
+	parseErr := models.UnmarshalIndicatorJSON(&indicatorResponse, data, indicatorName)
+	if parseErr != nil && !c.acceptPartial(parseErr) {
+		return nil, parseErr
+	}
+
+	if err := c.checkEmpty(len(indicatorResponse.IndicatorValues), indicatorName+" values"); err != nil {
+		return nil, err
+	}
+
+	return &indicatorResponse, parseErr
+}
+
+// GetCurrencyExchangeRate retrieves currency exchange rates based on the provided parameters.
+func (c *Client) GetCurrencyExchangeRate(params models.CurrencyExchangeParams) (*models.CurrencyExchangeRateResponse, error) {
+	return c.GetCurrencyExchangeRateContext(context.Background(), params)
+}
+
+// GetCurrencyExchangeRateContext is like GetCurrencyExchangeRate but aborts when ctx is canceled.
+func (c *Client) GetCurrencyExchangeRateContext(ctx context.Context, params models.CurrencyExchangeParams) (*models.CurrencyExchangeRateResponse, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "CURRENCY_EXCHANGE_RATE")
+	queryParams.Add("from_currency", params.FromCurrency)
+	queryParams.Add("to_currency", params.ToCurrency)
+	queryParams.Add("apikey", c.apiKey)
+
+	data, err := c.do(ctx, queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRateData := &models.CurrencyExchangeRateResponse{}
+	err = json.Unmarshal(data, exchangeRateData)
+	if err != nil {
+		return nil, err
+	}
+
+	return exchangeRateData, nil
+}
+
+// GetCryptoExchangeRatesRaw retrieves crypto exchange rates based on the provided parameters
+// and returns the unparsed response body.
+func (c *Client) GetCryptoExchangeRatesRaw(params models.CryptoExchangeRateParams) ([]byte, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "CURRENCY_EXCHANGE_RATE")
+	queryParams.Add("from_currency", params.FromCurrency)
+	queryParams.Add("to_currency", params.ToCurrency)
+	queryParams.Add("apikey", c.apiKey)
+
+	return c.do(context.Background(), queryParams)
+}
+
+// GetCryptoExchangeRates retrieves crypto exchange rates based on the provided parameters.
+// The crypto exchange rate shares the "Realtime Currency Exchange Rate" shape of physical currencies.
+func (c *Client) GetCryptoExchangeRates(params models.CryptoExchangeRateParams) (*models.CurrencyExchangeRateResponse, error) {
+	data, err := c.GetCryptoExchangeRatesRaw(params)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRateData := &models.CurrencyExchangeRateResponse{}
+	err = json.Unmarshal(data, exchangeRateData)
+	if err != nil {
+		return nil, err
+	}
+
+	return exchangeRateData, nil
+}
+
+// GetListingStatus retrieves the active or delisted symbols as of the given date.
+// An empty date returns the latest snapshot and an empty state defaults to active.
+func (c *Client) GetListingStatus(date string, state string) ([]models.ListingStatus, error) {
+	if state != "" && state != "active" && state != "delisted" {
+		return nil, fmt.Errorf("invalid listing state %q: must be \"active\" or \"delisted\"", state)
+	}
+
+	queryParams := url.Values{}
+	queryParams.Add("function", "LISTING_STATUS")
+	if date != "" {
+		queryParams.Add("date", date)
+	}
+	if state != "" {
+		queryParams.Add("state", state)
+	}
+	queryParams.Add("apikey", c.apiKey)
+
+	data, err := c.do(context.Background(), queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.ParseListingStatusCSV(data)
+}
+
+// GetTopGainersLosers retrieves the top gainers, losers, and most actively traded US tickers.
+func (c *Client) GetTopGainersLosers() (*models.MarketMovers, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "TOP_GAINERS_LOSERS")
+	queryParams.Add("apikey", c.apiKey)
+
+	data, err := c.do(context.Background(), queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	movers := &models.MarketMovers{}
+	err = json.Unmarshal(data, movers)
+	if err != nil {
+		return nil, err
+	}
+
+	return movers, nil
+}
+
+// GetMarketStatus retrieves the current open or closed status of the major trading venues.
+func (c *Client) GetMarketStatus() (*models.MarketStatus, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "MARKET_STATUS")
+	queryParams.Add("apikey", c.apiKey)
 
-	if monthStr, ok := params.Month.(string); ok {
-		queryParams.Add("month", monthStr)
-	} else if monthPtr, ok := params.Month.(*string); ok {
-		queryParams.Add("month", *monthPtr)
+	data, err := c.do(context.Background(), queryParams)
+	if err != nil {
+		return nil, err
 	}
 
-	if outputStr, ok := params.OutputSize.(string); ok {
-		queryParams.Add("outputsize", outputStr)
-	} else if outputPtr, ok := params.OutputSize.(*string); ok {
-		queryParams.Add("outputsize", *outputPtr)
+	if err := requireSection(data, "markets"); err != nil {
+		return nil, err
+	}
+
+	status := &models.MarketStatus{}
+	err = json.Unmarshal(data, status)
+	if err != nil {
+		return nil, err
 	}
 
-	if dataTypeStr, ok := params.DataType.(string); ok {
-		queryParams.Add("datatype", dataTypeStr)
-	} else if dataTypePtr, ok := params.DataType.(*string); ok {
-		queryParams.Add("datatype", *dataTypePtr)
+	return status, nil
+}
+
+// GetNewsSentiment retrieves news articles with their sentiment, filtered and ordered by params.
+func (c *Client) GetNewsSentiment(params models.NewsParams) (*models.NewsSentiment, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
 
+	queryParams := url.Values{}
+	queryParams.Add("function", "NEWS_SENTIMENT")
+	if len(params.Tickers) > 0 {
+		queryParams.Add("tickers", strings.Join(params.Tickers, ","))
+	}
+	if len(params.Topics) > 0 {
+		queryParams.Add("topics", strings.Join(params.Topics, ","))
+	}
+	if !params.TimeFrom.IsZero() {
+		queryParams.Add("time_from", params.TimeFrom.UTC().Format(models.NewsTimeLayout))
+	}
+	if !params.TimeTo.IsZero() {
+		queryParams.Add("time_to", params.TimeTo.UTC().Format(models.NewsTimeLayout))
+	}
+	if params.Sort != "" {
+		queryParams.Add("sort", string(params.Sort))
+	}
+	if params.Limit != 0 {
+		queryParams.Add("limit", strconv.Itoa(params.Limit))
+	}
 	queryParams.Add("apikey", c.apiKey)
 
-	resp, err := http.Get(alphaVantageURL + "?" + queryParams.Encode())
+	data, err := c.do(context.Background(), queryParams)
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	if err := requireSection(data, "feed"); err != nil {
+		return nil, err
+	}
+
+	news := &models.NewsSentiment{}
+	if err := json.Unmarshal(data, news); err != nil {
+		return nil, err
+	}
 
-	return ioutil.ReadAll(resp.Body)
+	return news, nil
 }
 
-// GetIndicatorData retrieves indicator data based on the provided parameters.
-func (c *Client) GetIndicatorData(params models.IndicatorParams) ([]byte, error) {
+// GetSplits retrieves the historical stock splits of symbol, oldest first.
+func (c *Client) GetSplits(symbol string) ([]models.Split, error) {
 	queryParams := url.Values{}
-	queryParams.Add("function", params.Function)
-	queryParams.Add("symbol", params.Symbol)
-	queryParams.Add("interval", params.Interval)
-	queryParams.Add("time_period", fmt.Sprintf("%d", params.TimePeriod))
-	queryParams.Add("series_type", params.SeriesType)
+	queryParams.Add("function", "SPLITS")
+	queryParams.Add("symbol", symbol)
+	queryParams.Add("apikey", c.apiKey)
 
-	if params.DataType != "" {
-		queryParams.Add("datatype", params.DataType)
+	data, err := c.do(context.Background(), queryParams)
+	if err != nil {
+		return nil, err
 	}
 
-	if params.Month != "" {
-		queryParams.Add("month", params.Month)
+	if err := requireSection(data, "data"); err != nil {
+		return nil, err
 	}
 
-	if params.OutputSize != "" {
-		queryParams.Add("outputsize", params.OutputSize)
+	var splits []models.Split
+	if err := models.UnmarshalSplitsJSON(&splits, data); err != nil {
+		return nil, err
 	}
 
+	return splits, nil
+}
+
+// GetDividends retrieves the historical and declared future dividends of symbol, oldest first.
+func (c *Client) GetDividends(symbol string) ([]models.Dividend, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "DIVIDENDS")
+	queryParams.Add("symbol", symbol)
 	queryParams.Add("apikey", c.apiKey)
 
-	resp, err := http.Get(alphaVantageURL + "?" + queryParams.Encode())
+	data, err := c.do(context.Background(), queryParams)
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	if err := requireSection(data, "data"); err != nil {
+		return nil, err
+	}
+
+	var dividends []models.Dividend
+	if err := models.UnmarshalDividendsJSON(&dividends, data); err != nil {
+		return nil, err
+	}
 
-	return ioutil.ReadAll(resp.Body)
+	return dividends, nil
 }
 
+// GetRealtimeOptions retrieves the current options chain of symbol, including the greeks.
+func (c *Client) GetRealtimeOptions(symbol string) (*models.OptionsChain, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "REALTIME_OPTIONS")
+	queryParams.Add("symbol", symbol)
+	queryParams.Add("require_greeks", "true")
 
-func (c *Client) getIndicator(indicatorName string, params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	// Add the function name to the params
-	params.Function = indicatorName
-	// Fetch the data using HTTP, similar to before.
-	data, err := c.GetIndicatorData(params)
-	if err != nil {
-		return nil, err
+	return c.getOptions(queryParams)
+}
+
+// GetHistoricalOptions retrieves the options chain of symbol as of date (YYYY-MM-DD).
+// An empty date returns the chain of the previous trading session.
+func (c *Client) GetHistoricalOptions(symbol string, date string) (*models.OptionsChain, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "HISTORICAL_OPTIONS")
+	queryParams.Add("symbol", symbol)
+	if date != "" {
+		queryParams.Add("date", date)
 	}
 
-	var indicatorResponse models.IndicatorResponse
-	if err := models.UnmarshalIndicatorJSON(&indicatorResponse, data, indicatorName); err != nil {
+	return c.getOptions(queryParams)
+}
+
+// getOptions retrieves and parses an options chain.
+func (c *Client) getOptions(queryParams url.Values) (*models.OptionsChain, error) {
+	chain := &models.OptionsChain{}
+	if err := c.getDataSeries(queryParams, chain); err != nil {
 		return nil, err
 	}
 
-	return &indicatorResponse, nil
+	return chain, nil
 }
 
-// GetCurrencyExchangeRate retrieves currency exchange rates based on the provided parameters.
-func (c *Client) GetCurrencyExchangeRate(params models.CurrencyExchangeParams) (*models.CurrencyExchangeRateResponse, error) {
+// getFundamentals retrieves the given fundamental data function for symbol and unmarshals it into v.
+func (c *Client) getFundamentals(function string, symbol string, v interface{}) error {
 	queryParams := url.Values{}
-	queryParams.Add("function", "CURRENCY_EXCHANGE_RATE")
-	queryParams.Add("from_currency", params.FromCurrency)
-	queryParams.Add("to_currency", params.ToCurrency)
+	queryParams.Add("function", function)
+	queryParams.Add("symbol", symbol)
 	queryParams.Add("apikey", c.apiKey)
 
-	resp, err := http.Get(alphaVantageURL + "?" + queryParams.Encode())
+	data, err := c.do(context.Background(), queryParams)
 	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// GetIncomeStatement retrieves the annual and quarterly income statements for the symbol.
+func (c *Client) GetIncomeStatement(symbol string) (*models.IncomeStatement, error) {
+	incomeStatement := &models.IncomeStatement{}
+	if err := c.getFundamentals("INCOME_STATEMENT", symbol, incomeStatement); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return incomeStatement, nil
+}
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+// GetBalanceSheet retrieves the annual and quarterly balance sheets for the symbol.
+func (c *Client) GetBalanceSheet(symbol string) (*models.BalanceSheet, error) {
+	balanceSheet := &models.BalanceSheet{}
+	if err := c.getFundamentals("BALANCE_SHEET", symbol, balanceSheet); err != nil {
 		return nil, err
 	}
+	return balanceSheet, nil
+}
 
-	exchangeRateData := &models.CurrencyExchangeRateResponse{}
-	err = json.Unmarshal(data, exchangeRateData)
+// GetCashFlow retrieves the annual and quarterly cash flow statements for the symbol.
+func (c *Client) GetCashFlow(symbol string) (*models.CashFlow, error) {
+	cashFlow := &models.CashFlow{}
+	if err := c.getFundamentals("CASH_FLOW", symbol, cashFlow); err != nil {
+		return nil, err
+	}
+	return cashFlow, nil
+}
+
+// getDataSeries retrieves a {"data": [{"date", "value"}]} shaped function and unmarshals it into v.
+func (c *Client) getDataSeries(queryParams url.Values, v interface{}) error {
+	queryParams.Add("apikey", c.apiKey)
+
+	data, err := c.do(context.Background(), queryParams)
 	if err != nil {
+		return err
+	}
+
+	if err := requireSection(data, "data"); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// getCommodity retrieves the given commodity function at the given interval.
+// An empty interval leaves the API default.
+func (c *Client) getCommodity(function string, interval string) (*models.CommoditySeries, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", function)
+	if interval != "" {
+		queryParams.Add("interval", interval)
+	}
+
+	commodity := &models.CommoditySeries{}
+	if err := c.getDataSeries(queryParams, commodity); err != nil {
 		return nil, err
 	}
+	return commodity, nil
+}
 
-	return exchangeRateData, nil
+// GetWTI retrieves West Texas Intermediate crude oil prices (daily, weekly or monthly).
+func (c *Client) GetWTI(interval string) (*models.CommoditySeries, error) {
+	return c.getCommodity("WTI", interval)
 }
 
-// GetCryptoExchangeRates retrieves crypto exchange rates based on the provided parameters.
-func (c *Client) GetCryptoExchangeRates(params models.CryptoExchangeRateParams) (*models.CurrencyExchangeRateResponse, error) {
+// GetBrent retrieves Brent crude oil prices (daily, weekly or monthly).
+func (c *Client) GetBrent(interval string) (*models.CommoditySeries, error) {
+	return c.getCommodity("BRENT", interval)
+}
+
+// GetNaturalGas retrieves Henry Hub natural gas spot prices (daily, weekly or monthly).
+func (c *Client) GetNaturalGas(interval string) (*models.CommoditySeries, error) {
+	return c.getCommodity("NATURAL_GAS", interval)
+}
+
+// getEconomicIndicator retrieves the given economic indicator function.
+// Empty interval and maturity leave the API defaults.
+func (c *Client) getEconomicIndicator(function string, interval string, maturity string) (*models.EconomicSeries, error) {
 	queryParams := url.Values{}
-	queryParams.Add("function", "CURRENCY_EXCHANGE_RATE")
-	queryParams.Add("from_currency", params.FromCurrency)
-	queryParams.Add("to_currency", params.ToCurrency)
+	queryParams.Add("function", function)
+	if interval != "" {
+		queryParams.Add("interval", interval)
+	}
+	if maturity != "" {
+		queryParams.Add("maturity", maturity)
+	}
+
+	series := &models.EconomicSeries{}
+	if err := c.getDataSeries(queryParams, series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// GetRealGDP retrieves the US real GDP (quarterly or annual).
+func (c *Client) GetRealGDP(interval string) (*models.EconomicSeries, error) {
+	return c.getEconomicIndicator("REAL_GDP", interval, "")
+}
+
+// GetCPI retrieves the US consumer price index (monthly or semiannual).
+func (c *Client) GetCPI(interval string) (*models.EconomicSeries, error) {
+	return c.getEconomicIndicator("CPI", interval, "")
+}
+
+// GetInflation retrieves the annual US inflation rate.
+func (c *Client) GetInflation() (*models.EconomicSeries, error) {
+	return c.getEconomicIndicator("INFLATION", "", "")
+}
+
+// GetUnemployment retrieves the monthly US unemployment rate.
+func (c *Client) GetUnemployment() (*models.EconomicSeries, error) {
+	return c.getEconomicIndicator("UNEMPLOYMENT", "", "")
+}
+
+// GetFederalFundsRate retrieves the US federal funds rate (daily, weekly or monthly).
+func (c *Client) GetFederalFundsRate(interval string) (*models.EconomicSeries, error) {
+	return c.getEconomicIndicator("FEDERAL_FUNDS_RATE", interval, "")
+}
+
+// GetTreasuryYield retrieves the US treasury yield (daily, weekly or monthly) for the given
+// maturity (3month, 2year, 5year, 7year, 10year or 30year).
+func (c *Client) GetTreasuryYield(interval, maturity string) (*models.EconomicSeries, error) {
+	return c.getEconomicIndicator("TREASURY_YIELD", interval, maturity)
+}
+
+// GetFixedWindowAnalytics computes the requested return statistics across symbols server-side.
+func (c *Client) GetFixedWindowAnalytics(params models.AnalyticsParams) (*models.AnalyticsResult, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "ANALYTICS_FIXED_WINDOW")
+	queryParams.Add("SYMBOLS", strings.Join(params.Symbols, ","))
+	for _, r := range params.Range {
+		queryParams.Add("RANGE", r)
+	}
+	queryParams.Add("INTERVAL", params.Interval)
+	if params.OHLC != "" {
+		queryParams.Add("OHLC", params.OHLC)
+	}
+	queryParams.Add("CALCULATIONS", strings.Join(params.Calculations, ","))
 	queryParams.Add("apikey", c.apiKey)
 
-	resp, err := http.Get(alphaVantageURL + "?" + queryParams.Encode())
+	data, err := c.do(context.Background(), queryParams)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+	if err := requireSection(data, "payload"); err != nil {
 		return nil, err
 	}
 
-	exchangeRateData := &models.CurrencyExchangeRateResponse{}
-	err = json.Unmarshal(data, exchangeRateData)
+	analytics := &models.AnalyticsResult{}
+	err = json.Unmarshal(data, analytics)
 	if err != nil {
 		return nil, err
 	}
 
-	return exchangeRateData, nil
+	return analytics, nil
 }
 
 // getCryptoData retrieves crypto data based on the provided parameters.
+// The market is upper-cased, as the currency in the keys of the response is matched against it.
 func (c *Client) getCryptoData(functionType string, params models.CryptoParams) (*models.CryptoSeriesResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
 	queryParams := url.Values{}
 	queryParams.Add("function", functionType)
 	queryParams.Add("symbol", params.Symbol)
 	queryParams.Add("interval", params.Interval)
-	queryParams.Add("market", params.Market)
-	if params.OutputSize != "" {
-		queryParams.Add("outputsize", params.OutputSize)
-	}
-	if params.DataType != "" {
-		queryParams.Add("datatype", params.DataType)
+	queryParams.Add("market", models.NormalizeMarket(params.Market))
+	c.addOutputSize(queryParams, params.OutputSize)
+	addDataType(queryParams, params.DataType)
+	if params.Entitlement != "" {
+		queryParams.Add("entitlement", params.Entitlement)
 	}
 	queryParams.Add("apikey", c.apiKey)
 
-	resp, err := http.Get(alphaVantageURL + "?" + queryParams.Encode())
+	data, err := c.do(context.Background(), queryParams)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+	if err := requireSection(data, "Time Series"); err != nil {
 		return nil, err
 	}
 
 	cryptoData := &models.CryptoSeriesResponse{}
 	err = models.UnmarshalCryptoJSON(cryptoData, data)
+	if err != nil && !c.acceptPartial(err) {
+		return nil, err
+	}
+	parseErr := err
+
+	if err := c.checkEmpty(len(cryptoData.TimeSeries), "crypto series"); err != nil {
+		return nil, err
+	}
+
+	return cryptoData, parseErr
+}
+
+// GetCryptoRating retrieves the Fundamental Crypto Asset Score (FCAS) of the digital currency symbol, e.g. "BTC".
+func (c *Client) GetCryptoRating(symbol string) (*models.CryptoRating, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", "CRYPTO_RATING")
+	queryParams.Add("symbol", symbol)
+	queryParams.Add("apikey", c.apiKey)
+
+	data, err := c.do(context.Background(), queryParams)
 	if err != nil {
 		return nil, err
 	}
 
-	return cryptoData, nil
+	if err := requireSection(data, "Crypto Rating"); err != nil {
+		return nil, err
+	}
+
+	rating := &models.CryptoRating{}
+	if err := json.Unmarshal(data, rating); err != nil {
+		return nil, err
+	}
+
+	return rating, nil
 }
 
 // GetCryptoIntraday retrieves intraday crypto data based on the provided parameters.
@@ -235,127 +1230,266 @@ func (c *Client) GetCryptoMonthly(params models.CryptoParams) (*models.CryptoSer
 	return c.getCryptoData("DIGITAL_CURRENCY_MONTHLY", params)
 }
 
+// getFXData retrieves FX data based on the provided parameters.
+func (c *Client) getFXData(functionType string, params models.FXParams) (*models.FXTimeSeries, error) {
+	queryParams := url.Values{}
+	queryParams.Add("function", functionType)
+	queryParams.Add("from_symbol", params.FromSymbol)
+	queryParams.Add("to_symbol", params.ToSymbol)
+	if params.Interval != "" {
+		queryParams.Add("interval", params.Interval)
+	}
+	c.addOutputSize(queryParams, params.OutputSize)
+	addDataType(queryParams, params.DataType)
+	queryParams.Add("apikey", c.apiKey)
+
+	data, err := c.do(context.Background(), queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireSection(data, "Time Series FX"); err != nil {
+		return nil, err
+	}
+
+	fxData := &models.FXTimeSeries{}
+	err = json.Unmarshal(data, fxData)
+	if err != nil {
+		return nil, err
+	}
+
+	return fxData, nil
+}
+
+// GetFXIntraday retrieves intraday FX data based on the provided parameters.
+func (c *Client) GetFXIntraday(params models.FXParams) (*models.FXTimeSeries, error) {
+	return c.getFXData("FX_INTRADAY", params)
+}
+
+// GetFXDaily retrieves daily FX data based on the provided parameters.
+func (c *Client) GetFXDaily(params models.FXParams) (*models.FXTimeSeries, error) {
+	return c.getFXData("FX_DAILY", params)
+}
+
+// GetFXWeekly retrieves weekly FX data based on the provided parameters.
+func (c *Client) GetFXWeekly(params models.FXParams) (*models.FXTimeSeries, error) {
+	return c.getFXData("FX_WEEKLY", params)
+}
+
+// GetFXMonthly retrieves monthly FX data based on the provided parameters.
+func (c *Client) GetFXMonthly(params models.FXParams) (*models.FXTimeSeries, error) {
+	return c.getFXData("FX_MONTHLY", params)
+}
+
 // GetIntraday retrieves intraday data based on the provided parameters.
 // It returns a TimeSeriesIntraday and an error if there is any.
 func (c *Client) GetIntraday(params models.TimeSeriesParams) (models.TimeSeriesIntraday, error) {
-	data, err := c.getTimeSeriesData("TIME_SERIES_INTRADAY", params)
+	return c.getIntraday(context.Background(), params)
+}
+
+// getIntraday is GetIntraday bound to the given context.
+func (c *Client) getIntraday(ctx context.Context, params models.TimeSeriesParams) (models.TimeSeriesIntraday, error) {
+	data, err := c.getTimeSeriesData(ctx, "TIME_SERIES_INTRADAY", params)
 	if err != nil {
 		return models.TimeSeriesIntraday{}, err
 	}
 
 	var intradayData models.TimeSeriesIntraday
 	err = json.Unmarshal(data, &intradayData)
-	if err != nil {
+	if err != nil && !c.acceptPartial(err) {
+		return models.TimeSeriesIntraday{}, err
+	}
+	parseErr := err
+
+	if err := c.checkEmpty(len(intradayData.TimeSeries), "intraday series"); err != nil {
 		return models.TimeSeriesIntraday{}, err
 	}
 
-	return intradayData, nil
+	// The response does not say whether it is adjusted; the API adjusts unless asked not to
+	intradayData.MetaData.Adjusted = params.Adjusted == nil || *params.Adjusted
+
+	if c.location != nil {
+		if err := intradayData.Localize(); err != nil {
+			return models.TimeSeriesIntraday{}, err
+		}
+		intradayData.InLocation(c.location)
+	}
+
+	return intradayData, parseErr
+}
+
+// GetIntradayLatest retrieves the most recent intraday bar of symbol at the given intraday
+// interval, requesting the compact output size since only the last bar is kept. It returns
+// models.ErrEmptySeries if the series is empty. The bar is realtime or delayed depending on the
+// plan of the API key; use GetIntraday with an Entitlement for entitlement-gated data.
+func (c *Client) GetIntradayLatest(symbol string, interval models.Interval) (models.OHLCV, error) {
+	if !interval.IsIntraday() {
+		return models.OHLCV{}, fmt.Errorf("invalid interval %q: must be an intraday interval", interval)
+	}
+
+	intradayData, err := c.getIntraday(context.Background(), models.TimeSeriesParams{
+		Symbol:     symbol,
+		Interval:   interval,
+		OutputSize: models.OutputSizeCompact,
+	})
+	if err != nil && !c.acceptPartial(err) {
+		return models.OHLCV{}, err
+	}
+
+	bar, ok := intradayData.Latest()
+	if !ok {
+		return models.OHLCV{}, fmt.Errorf("%w: no entries in the intraday series", models.ErrEmptySeries)
+	}
+	return bar, err
 }
 
 // GetDaily retrieves daily data based on the provided parameters.
 // It returns a TimeSeriesDaily and an error if there is any.
 func (c *Client) GetDaily(params models.TimeSeriesParams) (models.TimeSeriesDaily, error) {
-	data, err := c.getTimeSeriesData("TIME_SERIES_DAILY", params)
+	return c.getDaily(context.Background(), params)
+}
+
+// getDaily is GetDaily bound to the given context.
+func (c *Client) getDaily(ctx context.Context, params models.TimeSeriesParams) (models.TimeSeriesDaily, error) {
+	data, err := c.getTimeSeriesData(ctx, "TIME_SERIES_DAILY", params)
 	if err != nil {
 		return models.TimeSeriesDaily{}, err
 	}
 
 	var dailyData models.TimeSeriesDaily
 	err = json.Unmarshal(data, &dailyData)
-	if err != nil {
+	if err != nil && !c.acceptPartial(err) {
 		return models.TimeSeriesDaily{}, err
 	}
+	parseErr := err
 
-	return dailyData, nil
+	if err := c.checkEmpty(len(dailyData.TimeSeries), "daily series"); err != nil {
+		return models.TimeSeriesDaily{}, err
+	}
+	return dailyData, parseErr
 }
 
 // GetDailyAdjusted retrieves daily adjusted data based on the provided parameters.
 // It returns a TimeSeriesDailyAdjusted and an error if there is any.
 func (c *Client) GetDailyAdjusted(params models.TimeSeriesParams) (models.TimeSeriesDailyAdjusted, error) {
-	data, err := c.getTimeSeriesData("TIME_SERIES_DAILY_ADJUSTED", params)
+	data, err := c.getTimeSeriesData(context.Background(), "TIME_SERIES_DAILY_ADJUSTED", params)
 	if err != nil {
 		return models.TimeSeriesDailyAdjusted{}, err
 	}
 
 	var dailyAdjustedData models.TimeSeriesDailyAdjusted
 	err = json.Unmarshal(data, &dailyAdjustedData)
-	if err != nil {
+	if err != nil && !c.acceptPartial(err) {
 		return models.TimeSeriesDailyAdjusted{}, err
 	}
-	return dailyAdjustedData, nil
+	parseErr := err
+
+	if err := c.checkEmpty(len(dailyAdjustedData.TimeSeries), "daily adjusted series"); err != nil {
+		return models.TimeSeriesDailyAdjusted{}, err
+	}
+	return dailyAdjustedData, parseErr
 }
 
 // GetWeekly retrieves weekly data based on the provided parameters.
 // It returns a TimeSeriesWeekly and an error if there is any.
 func (c *Client) GetWeekly(params models.TimeSeriesParams) (models.TimeSeriesWeekly, error) {
-	data, err := c.getTimeSeriesData("TIME_SERIES_WEEKLY", params)
+	data, err := c.getTimeSeriesData(context.Background(), "TIME_SERIES_WEEKLY", params)
 	if err != nil {
 		return models.TimeSeriesWeekly{}, err
 	}
 
 	var weeklyData models.TimeSeriesWeekly
 	err = json.Unmarshal(data, &weeklyData)
-	if err != nil {
+	if err != nil && !c.acceptPartial(err) {
 		return models.TimeSeriesWeekly{}, err
 	}
-	return weeklyData, nil
+	parseErr := err
+
+	if err := c.checkEmpty(len(weeklyData.TimeSeries), "weekly series"); err != nil {
+		return models.TimeSeriesWeekly{}, err
+	}
+	return weeklyData, parseErr
 }
 
 // GetWeeklyAdjusted retrieves weekly adjusted data based on the provided parameters.
 // It returns a TimeSeriesWeekly and an error if there is any.
 func (c *Client) GetWeeklyAdjusted(params models.TimeSeriesParams) (models.TimeSeriesWeekly, error) {
-	data, err := c.getTimeSeriesData("TIME_SERIES_WEEKLY_ADJUSTED", params)
+	data, err := c.getTimeSeriesData(context.Background(), "TIME_SERIES_WEEKLY_ADJUSTED", params)
 	if err != nil {
 		return models.TimeSeriesWeekly{}, err
 	}
 
 	var weeklyAdjustedData models.TimeSeriesWeekly
 	err = json.Unmarshal(data, &weeklyAdjustedData)
-	if err != nil {
+	if err != nil && !c.acceptPartial(err) {
 		return models.TimeSeriesWeekly{}, err
 	}
-	return weeklyAdjustedData, nil
+	parseErr := err
+
+	if err := c.checkEmpty(len(weeklyAdjustedData.TimeSeries), "weekly adjusted series"); err != nil {
+		return models.TimeSeriesWeekly{}, err
+	}
+	return weeklyAdjustedData, parseErr
 }
 
 // GetMonthly retrieves monthly data based on the provided parameters.
 // It returns a TimeSeriesMonthly and an error if there is any.
 func (c *Client) GetMonthly(params models.TimeSeriesParams) (models.TimeSeriesMonthly, error) {
-	data, err := c.getTimeSeriesData("TIME_SERIES_MONTHLY", params)
+	data, err := c.getTimeSeriesData(context.Background(), "TIME_SERIES_MONTHLY", params)
 	if err != nil {
 		return models.TimeSeriesMonthly{}, err
 	}
 
 	var monthlyData models.TimeSeriesMonthly
 	err = json.Unmarshal(data, &monthlyData)
-	if err != nil {
+	if err != nil && !c.acceptPartial(err) {
 		return models.TimeSeriesMonthly{}, err
 	}
-	return monthlyData, nil
+	parseErr := err
+
+	if err := c.checkEmpty(len(monthlyData.TimeSeries), "monthly series"); err != nil {
+		return models.TimeSeriesMonthly{}, err
+	}
+	return monthlyData, parseErr
 }
 
 // GetMonthlyAdjusted retrieves monthly adjusted data based on the provided parameters.
 // It returns a TimeSeriesMonthlyAdjusted and an error if there is any.
 func (c *Client) GetMonthlyAdjusted(params models.TimeSeriesParams) (models.TimeSeriesMonthlyAdjusted, error) {
-	data, err := c.getTimeSeriesData("TIME_SERIES_MONTHLY_ADJUSTED", params)
+	data, err := c.getTimeSeriesData(context.Background(), "TIME_SERIES_MONTHLY_ADJUSTED", params)
 	if err != nil {
 		return models.TimeSeriesMonthlyAdjusted{}, err
 	}
 
 	var monthlyAdjustedData models.TimeSeriesMonthlyAdjusted
 	err = json.Unmarshal(data, &monthlyAdjustedData)
-	if err != nil {
+	if err != nil && !c.acceptPartial(err) {
 		return models.TimeSeriesMonthlyAdjusted{}, err
 	}
-	return monthlyAdjustedData, nil
+	parseErr := err
+
+	if err := c.checkEmpty(len(monthlyAdjustedData.TimeSeries), "monthly adjusted series"); err != nil {
+		return models.TimeSeriesMonthlyAdjusted{}, err
+	}
+	return monthlyAdjustedData, parseErr
 }
 // GetQuoteEndpoint retrieves the quote endpoint based on the provided parameters.
 // It returns a Quote and an error if there is any.
 func (c *Client) GetQuoteEndpoint(params models.TimeSeriesParams) (models.Quote, error) {
-	data, err := c.getTimeSeriesData("GLOBAL_QUOTE", params)
+	return c.getQuote(context.Background(), params)
+}
+
+// getQuote is GetQuoteEndpoint bound to the given context.
+func (c *Client) getQuote(ctx context.Context, params models.TimeSeriesParams) (models.Quote, error) {
+	data, err := c.getTimeSeriesData(ctx, "GLOBAL_QUOTE", params)
 	if err != nil {
 		return models.Quote{}, err
 	}
 
+	if params.DataType == models.DataTypeCSV {
+		return models.ParseQuoteCSV(data)
+	}
+
 	var quote models.Quote
 	err = json.Unmarshal(data, &quote)
 	if err != nil {
@@ -364,268 +1498,356 @@ func (c *Client) GetQuoteEndpoint(params models.TimeSeriesParams) (models.Quote,
 	return quote, nil
 }
 
+// GetQuote retrieves the latest quote of symbol. It is GetQuoteEndpoint with default parameters.
+func (c *Client) GetQuote(symbol string) (models.Quote, error) {
+	return c.GetQuoteEndpoint(models.TimeSeriesParams{Symbol: symbol})
+}
+
+// GetDailySymbol retrieves the compact daily series of symbol. It is GetDaily with default parameters.
+func (c *Client) GetDailySymbol(symbol string) (models.TimeSeriesDaily, error) {
+	return c.GetDaily(models.TimeSeriesParams{Symbol: symbol})
+}
+
+// maxBulkQuoteSymbols is the number of symbols accepted by a single REALTIME_BULK_QUOTES request.
+const maxBulkQuoteSymbols = 100
+
+// GetBulkQuotes retrieves realtime quotes for up to 100 symbols in a single request. Quotes
+// with malformed fields are returned with those fields at zero, together with a
+// models.ParseErrors naming the symbols concerned.
+func (c *Client) GetBulkQuotes(symbols []string) ([]models.Quote, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols given")
+	}
+	if len(symbols) > maxBulkQuoteSymbols {
+		return nil, fmt.Errorf("too many symbols: %d given, at most %d are allowed", len(symbols), maxBulkQuoteSymbols)
+	}
+
+	queryParams := url.Values{}
+	queryParams.Add("function", "REALTIME_BULK_QUOTES")
+	queryParams.Add("symbol", strings.Join(symbols, ","))
+	queryParams.Add("apikey", c.apiKey)
+
+	data, err := c.do(context.Background(), queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireSection(data, "data"); err != nil {
+		return nil, err
+	}
+
+	// Rows with malformed fields are still returned, along with the ParseErrors describing them
+	var quotes []models.Quote
+	err = models.UnmarshalBulkQuotesJSON(&quotes, data)
+	var parseErrs models.ParseErrors
+	if err != nil && !errors.As(err, &parseErrs) {
+		return nil, err
+	}
+
+	return quotes, err
+}
+
 // Client methods for retrieving indicator data
 
+// indicatorFunctions is the set of technical indicator functions accepted by GetIndicator.
+var indicatorFunctions = map[string]bool{
+	"SMA": true, "EMA": true, "WMA": true, "DEMA": true, "TEMA": true, "TRIMA": true,
+	"KAMA": true, "MAMA": true, "VWAP": true, "T3": true, "MACD": true, "MACDEXT": true,
+	"STOCH": true, "STOCHF": true, "RSI": true, "STOCHRSI": true, "WILLR": true, "ADX": true,
+	"ADXR": true, "APO": true, "PPO": true, "MOM": true, "BOP": true, "CCI": true, "CMO": true,
+	"ROC": true, "ROCR": true, "AROON": true, "AROONOSC": true, "MFI": true, "TRIX": true,
+	"ULTOSC": true, "DX": true, "MINUS_DI": true, "PLUS_DI": true, "MINUS_DM": true,
+	"PLUS_DM": true, "BBANDS": true, "MIDPOINT": true, "MIDPRICE": true, "SAR": true,
+	"TRANGE": true, "ATR": true, "NATR": true, "AD": true, "ADOSC": true, "OBV": true,
+	"HT_TRENDLINE": true, "HT_SINE": true, "HT_TRENDMODE": true, "HT_DCPERIOD": true,
+	"HT_DCPHASE": true, "HT_PHASOR": true,
+}
+
+// GetIndicator retrieves the technical indicator named by function, e.g. "RSI", based on the
+// provided parameters. The name is matched case-insensitively against the supported indicators.
+func (c *Client) GetIndicator(function string, params models.IndicatorParams) (*models.IndicatorResponse, error) {
+	return c.getIndicatorByName(context.Background(), function, params)
+}
+
+// getIndicatorByName is GetIndicator bound to the given context. It is shared by the helpers
+// that take function names from the caller, so that they check them the same way.
+func (c *Client) getIndicatorByName(ctx context.Context, function string, params models.IndicatorParams) (*models.IndicatorResponse, error) {
+	function = normalizeIndicatorFunction(function)
+	if !indicatorFunctions[function] {
+		return nil, fmt.Errorf("unknown indicator function %q", function)
+	}
+	if function == "VWAP" && !params.Interval.IsIntraday() {
+		return nil, fmt.Errorf("VWAP requires an intraday interval (1min to 60min), got %q", params.Interval)
+	}
+	return c.getIndicator(ctx, function, params)
+}
+
+// normalizeIndicatorFunction returns function in the upper-case form of indicatorFunctions.
+func normalizeIndicatorFunction(function string) string {
+	return strings.ToUpper(strings.TrimSpace(function))
+}
+
 // GetSMA retrieves SMA data based on the provided parameters.
 func (c *Client) GetSMA(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("SMA", params)
+	return c.getIndicator(context.Background(), "SMA", params)
 }
 
 // GetEMA retrieves EMA data based on the provided parameters.
 func (c *Client) GetEMA(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("EMA", params)
+	return c.getIndicator(context.Background(), "EMA", params)
 }
 // GetWMA retrieves WMA data based on the provided parameters.
 func (c *Client) GetWMA(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("WMA", params)
+	return c.getIndicator(context.Background(), "WMA", params)
 }
 
 // GetDEMA retrieves DEMA data based on the provided parameters.
 func (c *Client) GetDEMA(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("DEMA", params)
+	return c.getIndicator(context.Background(), "DEMA", params)
 }
 
 // GetTEMA retrieves TEMA data based on the provided parameters.
 func (c *Client) GetTEMA(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("TEMA", params)
+	return c.getIndicator(context.Background(), "TEMA", params)
 }
 
 // GetTRIMA retrieves TRIMA data based on the provided parameters.
 func (c *Client) GetTRIMA(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("TRIMA", params)
+	return c.getIndicator(context.Background(), "TRIMA", params)
 }
 
 // GetKAMA retrieves KAMA data based on the provided parameters.
 func (c *Client) GetKAMA(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("KAMA", params)
+	return c.getIndicator(context.Background(), "KAMA", params)
 }
 
 // GetMAMA retrieves MAMA data based on the provided parameters.
 func (c *Client) GetMAMA(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MAMA", params)
+	return c.getIndicator(context.Background(), "MAMA", params)
 }
 
 // GetVWAP retrieves VWAP data based on the provided parameters.
+// VWAP is only available for intraday intervals; TimePeriod and SeriesType are ignored.
 func (c *Client) GetVWAP(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("VWAP", params)
+	return c.getIndicatorByName(context.Background(), "VWAP", params)
 }
 
 // GetT3 retrieves T3 data based on the provided parameters.
 func (c *Client) GetT3(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("T3", params)
+	return c.getIndicator(context.Background(), "T3", params)
 }
 
 // GetMACD retrieves MACD data based on the provided parameters.
 func (c *Client) GetMACD(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MACD", params)
+	return c.getIndicator(context.Background(), "MACD", params)
 }
 
 // GetMACDEXT retrieves MACDEXT data based on the provided parameters.
 func (c *Client) GetMACDEXT(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MACDEXT", params)
+	return c.getIndicator(context.Background(), "MACDEXT", params)
 }
 
 // GetSTOCH retrieves STOCH data based on the provided parameters.
 func (c *Client) GetSTOCH(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("STOCH", params)
+	return c.getIndicator(context.Background(), "STOCH", params)
 }
 
 // GetSTOCHF retrieves STOCHF data based on the provided parameters.
 func (c *Client) GetSTOCHF(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("STOCHF", params)
+	return c.getIndicator(context.Background(), "STOCHF", params)
 }
 
 // GetRSI retrieves RSI data based on the provided parameters.
 func (c *Client) GetRSI(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("RSI", params)
+	return c.getIndicator(context.Background(), "RSI", params)
 }
 
 // GetSTOCHRSI retrieves STOCHRSI data based on the provided parameters.
 func (c *Client) GetSTOCHRSI(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("STOCHRSI", params)
+	return c.getIndicator(context.Background(), "STOCHRSI", params)
 }
 
 // GetWILLR retrieves WILLR data based on the provided parameters.
 func (c *Client) GetWILLR(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("WILLR", params)
+	return c.getIndicator(context.Background(), "WILLR", params)
 }
 
 // GetADX retrieves ADX data based on the provided parameters.
 func (c *Client) GetADX(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("ADX", params)
+	return c.getIndicator(context.Background(), "ADX", params)
 }
 
 // GetADXR retrieves ADXR data based on the provided parameters.
 func (c *Client) GetADXR(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("ADXR", params)
+	return c.getIndicator(context.Background(), "ADXR", params)
 }
 
 // GetAPO retrieves APO data based on the provided parameters.
 func (c *Client) GetAPO(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("APO", params)
+	return c.getIndicator(context.Background(), "APO", params)
 }
 
 // GetPPO retrieves PPO data based on the provided parameters.
 func (c *Client) GetPPO(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("PPO", params)
+	return c.getIndicator(context.Background(), "PPO", params)
 }
 
 // GetMOM retrieves MOM data based on the provided parameters.
 func (c *Client) GetMOM(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MOM", params)
+	return c.getIndicator(context.Background(), "MOM", params)
 }
 
 // GetBOP retrieves BOP data based on the provided parameters.
 func (c *Client) GetBOP(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("BOP", params)
+	return c.getIndicator(context.Background(), "BOP", params)
 }
 
 // GetCCI retrieves CCI data based on the provided parameters.
 func (c *Client) GetCCI(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("CCI", params)
+	return c.getIndicator(context.Background(), "CCI", params)
 }
 
 // GetCMO retrieves CMO data based on the provided parameters.
 func (c *Client) GetCMO(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("CMO", params)
+	return c.getIndicator(context.Background(), "CMO", params)
 }
 
 // GetROC retrieves ROC data based on the provided parameters.
 func (c *Client) GetROC(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("ROC", params)
+	return c.getIndicator(context.Background(), "ROC", params)
 }
 
 // GetROCR retrieves ROCR data based on the provided parameters.
 func (c *Client) GetROCR(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("ROCR", params)
+	return c.getIndicator(context.Background(), "ROCR", params)
 }
 
 // GetAROON retrieves AROON data based on the provided parameters.
 func (c *Client) GetAROON(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("AROON", params)
+	return c.getIndicator(context.Background(), "AROON", params)
 }
 
 // GetAROONOSC retrieves AROONOSC data based on the provided parameters.
 func (c *Client) GetAROONOSC(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("AROONOSC", params)
+	return c.getIndicator(context.Background(), "AROONOSC", params)
 }
 
 // GetMFI retrieves MFI data based on the provided parameters.
 func (c *Client) GetMFI(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MFI", params)
+	return c.getIndicator(context.Background(), "MFI", params)
 }
 
 // GetTRIX retrieves TRIX data based on the provided parameters.
 func (c *Client) GetTRIX(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("TRIX", params)
+	return c.getIndicator(context.Background(), "TRIX", params)
 }
 
 // GetULTOSC retrieves ULTOSC data based on the provided parameters.
 func (c *Client) GetULTOSC(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("ULTOSC", params)
+	return c.getIndicator(context.Background(), "ULTOSC", params)
 }
 
 // GetDX retrieves DX data based on the provided parameters.
 func (c *Client) GetDX(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("DX", params)
+	return c.getIndicator(context.Background(), "DX", params)
 }
 
 // GetMINUSDI retrieves MINUSDI data based on the provided parameters.
 func (c *Client) GetMINUSDI(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MINUS_DI", params)
+	return c.getIndicator(context.Background(), "MINUS_DI", params)
 }
 
 // GetPLUSDI retrieves PLUSDI data based on the provided parameters.
 func (c *Client) GetPLUSDI(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("PLUS_DI", params)
+	return c.getIndicator(context.Background(), "PLUS_DI", params)
 }
 
 // GetMINUSDM retrieves MINUSDM data based on the provided parameters.
 func (c *Client) GetMINUSDM(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MINUS_DM", params)
+	return c.getIndicator(context.Background(), "MINUS_DM", params)
 }
 
 // GetPLUSDM retrieves PLUSDM data based on the provided parameters.
 func (c *Client) GetPLUSDM(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("PLUS_DM", params)
+	return c.getIndicator(context.Background(), "PLUS_DM", params)
 }
 
 // GetBBANDS retrieves BBANDS data based on the provided parameters.
 func (c *Client) GetBBANDS(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("BBANDS", params)
+	return c.getIndicator(context.Background(), "BBANDS", params)
 }
 
 // GetMIDPOINT retrieves MIDPOINT data based on the provided parameters.
 func (c *Client) GetMIDPOINT(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MIDPOINT", params)
+	return c.getIndicator(context.Background(), "MIDPOINT", params)
 }
 
 // GetMIDPRICE retrieves MIDPRICE data based on the provided parameters.
 func (c *Client) GetMIDPRICE(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("MIDPRICE", params)
+	return c.getIndicator(context.Background(), "MIDPRICE", params)
 }
 
 // GetSAR retrieves SAR data based on the provided parameters.
 func (c *Client) GetSAR(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("SAR", params)
+	return c.getIndicator(context.Background(), "SAR", params)
 }
 
 // GetTRANGE retrieves TRANGE data based on the provided parameters.
 func (c *Client) GetTRANGE(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("TRANGE", params)
+	return c.getIndicator(context.Background(), "TRANGE", params)
 }
 
 // GetATR retrieves ATR data based on the provided parameters.
 func (c *Client) GetATR(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("ATR", params)
+	return c.getIndicator(context.Background(), "ATR", params)
 }
 
 // GetNATR retrieves NATR data based on the provided parameters.
 func (c *Client) GetNATR(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("NATR", params)
+	return c.getIndicator(context.Background(), "NATR", params)
 }
 
 // GetAD retrieves AD data based on the provided parameters.
 func (c *Client) GetAD(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("AD", params)
+	return c.getIndicator(context.Background(), "AD", params)
 }
 
 // GetADOSC retrieves ADOSC data based on the provided parameters.
 func (c *Client) GetADOSC(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("ADOSC", params)
+	return c.getIndicator(context.Background(), "ADOSC", params)
 }
 
 // GetOBV retrieves OBV data based on the provided parameters.
 func (c *Client) GetOBV(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("OBV", params)
+	return c.getIndicator(context.Background(), "OBV", params)
 }
 
 // GetHTTRENDLINE retrieves HT_TRENDLINE data based on the provided parameters.
 func (c *Client) GetHTTRENDLINE(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("HT_TRENDLINE", params)
+	return c.getIndicator(context.Background(), "HT_TRENDLINE", params)
 }
 
 // GetHTSINE retrieves HT_SINE data based on the provided parameters.
 func (c *Client) GetHTSINE(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("HT_SINE", params)
+	return c.getIndicator(context.Background(), "HT_SINE", params)
 }
 
 // GetHTTRENDMODE retrieves HT_TRENDMODE data based on the provided parameters.
 func (c *Client) GetHTTRENDMODE(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("HT_TRENDMODE", params)
+	return c.getIndicator(context.Background(), "HT_TRENDMODE", params)
 }
 
 // GetHTDCPERIOD retrieves HT_DCPERIOD data based on the provided parameters.
 func (c *Client) GetHTDCPERIOD(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("HT_DCPERIOD", params)
+	return c.getIndicator(context.Background(), "HT_DCPERIOD", params)
 }
 
 // GetHTDCPHASE retrieves HT_DCPHASE data based on the provided parameters.
 func (c *Client) GetHTDCPHASE(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("HT_DCPHASE", params)
+	return c.getIndicator(context.Background(), "HT_DCPHASE", params)
 }
 
 // GetHTPHASOR retrieves HT_PHASOR data based on the provided parameters.
 func (c *Client) GetHTPHASOR(params models.IndicatorParams) (*models.IndicatorResponse, error) {
-	return c.getIndicator("HT_PHASOR", params)
+	return c.getIndicator(context.Background(), "HT_PHASOR", params)
 }
\ No newline at end of file
@@ -0,0 +1,370 @@
+package client
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/masonJamesWheeler/alpha-vantage-go-wrapper/models"
+)
+
+func TestGetDailyFixture(t *testing.T) {
+	c := newTestClient(t, fixtureHandler(t, "daily.json"))
+
+	daily, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily.TimeSeries) != 3 || daily.TimeSeries[2].Close != 147.68 {
+		t.Errorf("unexpected series %+v", daily.TimeSeries)
+	}
+}
+
+func TestRateLimitNoteFixture(t *testing.T) {
+	c := newTestClient(t, fixtureHandler(t, "rate_limit_note.json"))
+
+	_, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+	var rateErr *models.RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("err = %v, want a *models.RateLimitError", err)
+	}
+	if rateErr.Function != "TIME_SERIES_DAILY" || rateErr.Query.Get("apikey") != "REDACTED" {
+		t.Errorf("unexpected request context %q %v", rateErr.Function, rateErr.Query)
+	}
+	if !errors.Is(err, models.ErrNoData) {
+		t.Errorf("err = %v, want it to wrap ErrNoData", err)
+	}
+}
+
+func TestErrorOnEmpty(t *testing.T) {
+	emptySection := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Meta Data": {"2. Symbol": "NEWCO"}, "Time Series (Daily)": {}}`))
+	}
+	noSection := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Meta Data": {"2. Symbol": "NEWCO"}}`))
+	}
+
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		errorOnEmpty bool
+		want         error
+	}{
+		{"empty section", emptySection, false, nil},
+		{"empty section with option", emptySection, true, models.ErrEmptySeries},
+		{"missing section", noSection, false, models.ErrNoData},
+		{"missing section with option", noSection, true, models.ErrNoData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.handler, WithErrorOnEmpty(tt.errorOnEmpty))
+			_, err := c.GetDaily(models.TimeSeriesParams{Symbol: "NEWCO"})
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			// An empty series is a case of no data, so callers checking ErrNoData see it too
+			if !errors.Is(err, models.ErrNoData) {
+				t.Errorf("err = %v, want it to wrap ErrNoData", err)
+			}
+		})
+	}
+}
+
+func TestGzipResponse(t *testing.T) {
+	body := readFixture(t, "daily.json")
+	var acceptEncoding string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write(body)
+		gz.Close()
+	})
+
+	daily, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acceptEncoding != "gzip" {
+		t.Errorf("Accept-Encoding = %q, want gzip", acceptEncoding)
+	}
+	if len(daily.TimeSeries) != 3 {
+		t.Errorf("got %d bars, want 3", len(daily.TimeSeries))
+	}
+}
+
+func TestGzipResponseCorrupt(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip"))
+	})
+
+	if _, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"}); err == nil {
+		t.Fatal("expected an error for a corrupt gzip body")
+	}
+}
+
+func TestPremiumEndpointFixture(t *testing.T) {
+	c := newTestClient(t, fixtureHandler(t, "premium_endpoint.json"))
+
+	_, err := c.GetDailyAdjusted(models.TimeSeriesParams{Symbol: "IBM"})
+	if !errors.Is(err, models.ErrPremiumEndpoint) {
+		t.Fatalf("err = %v, want ErrPremiumEndpoint", err)
+	}
+	var apiErr *models.APIError
+	if !errors.As(err, &apiErr) || apiErr.Function != "TIME_SERIES_DAILY_ADJUSTED" {
+		t.Fatalf("err = %#v, want *models.APIError for TIME_SERIES_DAILY_ADJUSTED", err)
+	}
+	// A premium message is not a rate limit, and retrying it with another key would not help
+	var rateErr *models.RateLimitError
+	if errors.As(err, &rateErr) {
+		t.Errorf("premium message reported as a rate limit: %v", err)
+	}
+}
+
+// closeCountingTransport is an http.RoundTripper that counts calls to CloseIdleConnections.
+type closeCountingTransport struct {
+	http.RoundTripper
+	closed int
+}
+
+func (t *closeCountingTransport) CloseIdleConnections() {
+	t.closed++
+}
+
+func TestCloseCallerClient(t *testing.T) {
+	transport := &closeCountingTransport{RoundTripper: http.DefaultTransport}
+	c := NewClient("test", WithHTTPClient(&http.Client{Transport: transport}))
+
+	c.Close()
+	if transport.closed != 0 {
+		t.Errorf("Close closed the idle connections of the caller's client")
+	}
+}
+
+func TestCloseOwnClient(t *testing.T) {
+	c := newTestClient(t, fixtureHandler(t, "daily.json"))
+
+	// The default client must not share the transport of http.DefaultClient
+	if c.httpClient == http.DefaultClient || c.httpClient.Transport == http.DefaultTransport {
+		t.Fatal("default client shares http.DefaultClient or its transport")
+	}
+	if _, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"}); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	// The client stays usable after Close
+	if _, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndicatorDuplicateFixture(t *testing.T) {
+	c := newTestClient(t, fixtureHandler(t, "rsi_duplicate.json"))
+
+	rsi, err := c.GetRSI(models.NewIndicatorParams("IBM", "5min", 14, "close"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rsi.IndicatorValues) != 3 || rsi.IndicatorValues[1].Values["RSI"] != 57.91 {
+		t.Errorf("unexpected values %+v", rsi.IndicatorValues)
+	}
+}
+
+// TestConcurrentRequests hammers one Client from many goroutines, sharing its key ring, rate
+// limiter and raw capture. Run it with -race.
+func TestConcurrentRequests(t *testing.T) {
+	srv := newKeyServer(t, "key1")
+	c := newTestClient(t, srv.ServeHTTP,
+		WithAPIKeys([]string{"key1", "key2", "key3"}),
+		WithRateLimit(600000),
+		WithRawCapture(),
+	)
+
+	const goroutines, requests = 20, 5
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*requests)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < requests; i++ {
+				daily, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+				if err == nil && len(daily.TimeSeries) != 3 {
+					err = fmt.Errorf("got %d bars, want 3", len(daily.TimeSeries))
+				}
+				if err != nil {
+					errs <- err
+				}
+				if raw := c.LastRaw(); len(raw) == 0 {
+					errs <- errors.New("no raw body captured")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	// Each request that drew the limited key was retried with another one
+	used := srv.Used()
+	if len(used) < goroutines*requests {
+		t.Errorf("%d requests served, want at least %d", len(used), goroutines*requests)
+	}
+}
+
+// TestPartialParse checks that the daily and intraday getters share one contract for a
+// response with malformed bars: strict parsing fails with the zero value, lenient parsing
+// returns the good bars with their ParseErrors, and a partial result left empty is still
+// subject to WithErrorOnEmpty.
+func TestPartialParse(t *testing.T) {
+	const (
+		daily = `{"Meta Data": {"2. Symbol": "IBM"}, "Time Series (Daily)": {
+			"2023-09-08": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "n/a"},
+			"2023-09-07": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "100"}}}`
+		dailyAllBad = `{"Meta Data": {"2. Symbol": "IBM"}, "Time Series (Daily)": {
+			"2023-09-08": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "n/a"}}}`
+		intraday = `{"Meta Data": {"2. Symbol": "IBM"}, "Time Series (5min)": {
+			"2023-09-08 16:00:00": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "n/a"},
+			"2023-09-08 15:55:00": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "100"}}}`
+		intradayAllBad = `{"Meta Data": {"2. Symbol": "IBM"}, "Time Series (5min)": {
+			"2023-09-08 16:00:00": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "n/a"}}}`
+	)
+	getDaily := func(c *Client) (int, error) {
+		series, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+		return len(series.TimeSeries), err
+	}
+	getIntraday := func(c *Client) (int, error) {
+		series, err := c.GetIntraday(models.TimeSeriesParams{Symbol: "IBM", Interval: "5min"})
+		return len(series.TimeSeries), err
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		get       func(*Client) (int, error)
+		opts      []Option
+		wantBars  int
+		wantParse bool
+		wantEmpty bool
+	}{
+		{"daily strict", daily, getDaily, nil, 0, true, false},
+		{"daily lenient", daily, getDaily, []Option{WithLenientParsing()}, 1, true, false},
+		{"daily lenient all bad", dailyAllBad, getDaily, []Option{WithLenientParsing()}, 0, true, false},
+		{"daily lenient all bad with option", dailyAllBad, getDaily, []Option{WithLenientParsing(), WithErrorOnEmpty(true)}, 0, false, true},
+		{"intraday strict", intraday, getIntraday, nil, 0, true, false},
+		{"intraday lenient", intraday, getIntraday, []Option{WithLenientParsing()}, 1, true, false},
+		{"intraday lenient all bad with option", intradayAllBad, getIntraday, []Option{WithLenientParsing(), WithErrorOnEmpty(true)}, 0, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}, tt.opts...)
+
+			bars, err := tt.get(c)
+			if bars != tt.wantBars {
+				t.Errorf("got %d bars, want %d", bars, tt.wantBars)
+			}
+			var parseErrs models.ParseErrors
+			if errors.As(err, &parseErrs) != tt.wantParse {
+				t.Errorf("err = %v, want ParseErrors: %v", err, tt.wantParse)
+			}
+			if errors.Is(err, models.ErrEmptySeries) != tt.wantEmpty {
+				t.Errorf("err = %v, want ErrEmptySeries: %v", err, tt.wantEmpty)
+			}
+		})
+	}
+}
+
+func TestGetIntradayLatest(t *testing.T) {
+	rec := &queryRecorder{body: string(readFixture(t, "intraday.json"))}
+	c := newTestClient(t, rec.ServeHTTP)
+
+	latest, err := c.GetIntradayLatest("IBM", models.Interval5Min)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2023, 9, 8, 19, 55, 0, 0, time.UTC); !latest.Timestamp.Equal(want) || latest.Close != 147.68 {
+		t.Errorf("latest = %+v, want the 19:55 bar", latest)
+	}
+	checkQuery(t, rec.Query(), map[string]string{"function": "TIME_SERIES_INTRADAY", "interval": "5min", "outputsize": "compact"})
+}
+
+func TestGetIntradayLatestEmpty(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Meta Data": {"2. Symbol": "NEWCO"}, "Time Series (5min)": {}}`))
+	})
+
+	// Without WithErrorOnEmpty the series is fetched, and the missing bar is still an error
+	if _, err := c.GetIntradayLatest("NEWCO", models.Interval5Min); !errors.Is(err, models.ErrEmptySeries) {
+		t.Fatalf("err = %v, want ErrEmptySeries", err)
+	}
+}
+
+func TestGetIntradayLatestInterval(t *testing.T) {
+	rec := &queryRecorder{body: string(readFixture(t, "intraday.json"))}
+	c := newTestClient(t, rec.ServeHTTP)
+
+	if _, err := c.GetIntradayLatest("IBM", models.IntervalDaily); err == nil {
+		t.Fatal("expected an error for a daily interval")
+	}
+	if rec.Query() != nil {
+		t.Error("request sent for an invalid interval")
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	c := newTestClient(t, fixtureHandler(t, "intraday.json"), WithLocation(tokyo))
+
+	intraday, err := c.GetIntraday(models.TimeSeriesParams{Symbol: "IBM", Interval: models.Interval5Min})
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, ok := intraday.Latest()
+	if !ok {
+		t.Fatal("empty series")
+	}
+	if latest.Timestamp.Location() != tokyo {
+		t.Errorf("location = %v, want Asia/Tokyo", latest.Timestamp.Location())
+	}
+	// The US/Eastern wall-clock time 19:55 is 23:55 UTC, and 08:55 the next day in Tokyo
+	if want := time.Date(2023, 9, 8, 23, 55, 0, 0, time.UTC); !latest.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", latest.Timestamp.UTC(), want)
+	}
+	if got := latest.Timestamp.Format("2006-01-02 15:04"); got != "2023-09-09 08:55" {
+		t.Errorf("Tokyo time = %s, want 2023-09-09 08:55", got)
+	}
+}
+
+func TestWithoutLocation(t *testing.T) {
+	c := newTestClient(t, fixtureHandler(t, "intraday.json"))
+
+	intraday, err := c.GetIntraday(models.TimeSeriesParams{Symbol: "IBM", Interval: models.Interval5Min})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without the option the wall-clock times are kept as UTC
+	latest, _ := intraday.Latest()
+	if want := time.Date(2023, 9, 8, 19, 55, 0, 0, time.UTC); !latest.Timestamp.Equal(want) || latest.Timestamp.Location() != time.UTC {
+		t.Errorf("timestamp = %v, want %v", latest.Timestamp, want)
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/masonJamesWheeler/alpha-vantage-go-wrapper/models"
+)
+
+func TestPeekError(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		rateLimit bool
+		target    error // wrapped by the returned error; nil when no error is expected
+	}{
+		{"note", `{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`, true, models.ErrNoData},
+		{"error message", `{"Error Message": "Invalid API call. Please retry or visit the documentation."}`, false, models.ErrNoData},
+		{"information", `{"Information": "The demo API key is for demo purposes only."}`, false, models.ErrNoData},
+		{"information rate limit", `{"Information": "We have detected your API key and our standard API rate limit is 25 requests per day."}`, true, models.ErrNoData},
+		{"information premium", `{"Information": "Thank you for using Alpha Vantage! This is a premium endpoint."}`, false, models.ErrPremiumEndpoint},
+		{"data with message key", `{"Information": "Daily prices", "Time Series (Daily)": {}}`, false, nil},
+		{"data", `{"Meta Data": {}, "Time Series (Daily)": {}}`, false, nil},
+		{"csv", "timestamp,open,high,low,close,volume\n2023-09-08,147.49,148.38,146.81,147.68,2805923\n", false, nil},
+		{"empty object", `{}`, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := peekError([]byte(tt.body))
+			if tt.target == nil {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.target) {
+				t.Fatalf("err = %v, want it to wrap %v", err, tt.target)
+			}
+			var rateErr *models.RateLimitError
+			if errors.As(err, &rateErr) != tt.rateLimit {
+				t.Errorf("err = %#v, rate limit %v", err, tt.rateLimit)
+			}
+			if !tt.rateLimit {
+				var apiErr *models.APIError
+				if !errors.As(err, &apiErr) || apiErr.Message == "" {
+					t.Errorf("err = %#v, want *models.APIError with the message", err)
+				}
+			}
+		})
+	}
+}
+
+func TestNonJSONResponse(t *testing.T) {
+	page := "<!DOCTYPE html><html><body><h1>Down for maintenance</h1></body></html>"
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     bool
+	}{
+		{"html content type", "text/html; charset=utf-8", page, true},
+		{"html with json content type", "application/json", "\n  " + page, true},
+		{"json", "application/json", string(readFixture(t, "daily.json")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			})
+			_, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, models.ErrNonJSONResponse) {
+				t.Fatalf("err = %v, want ErrNonJSONResponse", err)
+			}
+			if !strings.Contains(err.Error(), "maintenance") {
+				t.Errorf("error %q does not quote the body", err)
+			}
+		})
+	}
+}
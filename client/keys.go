@@ -0,0 +1,37 @@
+package client
+
+import (
+	"strings"
+	"sync"
+)
+
+// keyRing hands out API keys in round-robin order. It is safe for concurrent use.
+type keyRing struct {
+	mu   sync.Mutex
+	keys []string
+	next int
+}
+
+func newKeyRing(keys []string) *keyRing {
+	return &keyRing{keys: append([]string(nil), keys...)}
+}
+
+// Next returns every key, starting with the one to use for the next request, and advances the
+// ring by one. A request retries with the following keys in this order, so that concurrent
+// requests moving the ring on in between cannot make it try a key twice and skip another.
+func (k *keyRing) Next() []string {
+	k.mu.Lock()
+	start := k.next
+	k.next = (k.next + 1) % len(k.keys)
+	k.mu.Unlock()
+
+	keys := make([]string, 0, len(k.keys))
+	keys = append(keys, k.keys[start:]...)
+	return append(keys, k.keys[:start]...)
+}
+
+// isRateLimitMessage reports whether the "Information" message info is the rate-limit note.
+func isRateLimitMessage(info string) bool {
+	info = strings.ToLower(info)
+	return strings.Contains(info, "rate limit") || strings.Contains(info, "call frequency")
+}
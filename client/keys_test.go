@@ -0,0 +1,134 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/masonJamesWheeler/alpha-vantage-go-wrapper/models"
+)
+
+// keyServer answers requests made with a limited key with the rate-limit note and the others
+// with the daily fixture, recording the keys in the order they were used.
+type keyServer struct {
+	mu      sync.Mutex
+	limited map[string]bool
+	used    []string
+	note    []byte
+	daily   []byte
+}
+
+func newKeyServer(t *testing.T, limited ...string) *keyServer {
+	s := &keyServer{
+		limited: make(map[string]bool),
+		note:    readFixture(t, "rate_limit_note.json"),
+		daily:   readFixture(t, "daily.json"),
+	}
+	for _, key := range limited {
+		s.limited[key] = true
+	}
+	return s
+}
+
+func (s *keyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("apikey")
+	s.mu.Lock()
+	s.used = append(s.used, key)
+	limited := s.limited[key]
+	s.mu.Unlock()
+
+	if limited {
+		w.Write(s.note)
+		return
+	}
+	w.Write(s.daily)
+}
+
+// Used returns the keys of the requests made so far.
+func (s *keyServer) Used() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.used...)
+}
+
+func TestKeyRotationOnRateLimit(t *testing.T) {
+	srv := newKeyServer(t, "key1")
+	c := newTestClient(t, srv.ServeHTTP, WithAPIKeys([]string{"key1", "key2"}))
+
+	daily, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily.TimeSeries) != 3 {
+		t.Errorf("got %d bars, want 3", len(daily.TimeSeries))
+	}
+	if used := srv.Used(); len(used) != 2 || used[0] != "key1" || used[1] != "key2" {
+		t.Errorf("keys used = %v, want [key1 key2]", used)
+	}
+}
+
+func TestKeyRotationRoundRobin(t *testing.T) {
+	srv := newKeyServer(t)
+	c := newTestClient(t, srv.ServeHTTP, WithAPIKeys([]string{"key1", "key2", "key3"}))
+
+	for i := 0; i < 4; i++ {
+		if _, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"key1", "key2", "key3", "key1"}
+	used := srv.Used()
+	for i := range want {
+		if i >= len(used) || used[i] != want[i] {
+			t.Fatalf("keys used = %v, want %v", used, want)
+		}
+	}
+}
+
+func TestKeyRotationAllLimited(t *testing.T) {
+	srv := newKeyServer(t, "key1", "key2")
+	c := newTestClient(t, srv.ServeHTTP, WithAPIKeys([]string{"key1", "key2"}))
+
+	_, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+	var rateErr *models.RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("err = %v, want *models.RateLimitError", err)
+	}
+	// Every key is tried once, and no more
+	if used := srv.Used(); len(used) != 2 {
+		t.Errorf("keys used = %v, want each key once", used)
+	}
+}
+
+func TestKeyRingNext(t *testing.T) {
+	ring := newKeyRing([]string{"a", "b", "c"})
+	for _, want := range [][]string{{"a", "b", "c"}, {"b", "c", "a"}, {"c", "a", "b"}, {"a", "b", "c"}} {
+		if got := ring.Next(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Next() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestKeyRotationDataWithNote(t *testing.T) {
+	// A note carried next to the data is not a rate limit, so the data is kept and no other key tried
+	body := `{"Note": "Prices are delayed.", "Meta Data": {"2. Symbol": "IBM"}, "Time Series (Daily)": {
+		"2023-09-08": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "1"}}}`
+	var used []string
+	var mu sync.Mutex
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		used = append(used, r.URL.Query().Get("apikey"))
+		mu.Unlock()
+		w.Write([]byte(body))
+	}, WithAPIKeys([]string{"key1", "key2"}))
+
+	daily, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily.TimeSeries) != 1 || len(used) != 1 {
+		t.Errorf("got %d bars with keys %v, want 1 bar with one key", len(daily.TimeSeries), used)
+	}
+}
@@ -0,0 +1,682 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/masonJamesWheeler/alpha-vantage-go-wrapper/models"
+)
+
+const dailyBody = `{"Meta Data": {"2. Symbol": "IBM"}, "Time Series (Daily)": {
+	"2023-09-08": {"1. open": "147.49", "2. high": "148.38", "3. low": "146.81", "4. close": "147.68", "5. volume": "2805923"}}}`
+
+const rsiBody = `{"Meta Data": {"1: Symbol": "IBM"}, "Technical Analysis: RSI": {"2023-09-08 19:55": {"RSI": "55.2311"}}}`
+
+const cryptoBody = `{"Meta Data": {"4. Market Code": "USD"}, "Time Series (Digital Currency Daily)": {
+	"2023-09-10": {"1. open": "25832.23", "2. high": "25911.07", "3. low": "25061.26", "4. close": "25280.73", "5. volume": "22064"}}}`
+
+func TestEntitlementQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		entitlement string
+		call        func(c *Client, entitlement string) error
+	}{
+		{"time series", dailyBody, "realtime", func(c *Client, entitlement string) error {
+			_, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM", Entitlement: entitlement})
+			return err
+		}},
+		{"indicator", rsiBody, "delayed", func(c *Client, entitlement string) error {
+			_, err := c.GetIndicator("RSI", models.IndicatorParams{Symbol: "IBM", Interval: models.Interval5Min, TimePeriod: 14, Entitlement: entitlement})
+			return err
+		}},
+		{"crypto", cryptoBody, "realtime", func(c *Client, entitlement string) error {
+			_, err := c.GetCryptoDaily(models.CryptoParams{Symbol: "BTC", Market: "USD", Entitlement: entitlement})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &queryRecorder{body: tt.body}
+			c := newTestClient(t, recorder.ServeHTTP)
+
+			if err := tt.call(c, tt.entitlement); err != nil {
+				t.Fatal(err)
+			}
+			checkQuery(t, recorder.Query(), map[string]string{"entitlement": tt.entitlement})
+
+			if err := tt.call(c, ""); err != nil {
+				t.Fatal(err)
+			}
+			checkQuery(t, recorder.Query(), nil, "entitlement")
+		})
+	}
+}
+
+func TestVWAPQuery(t *testing.T) {
+	recorder := &queryRecorder{body: `{"Meta Data": {"1: Symbol": "IBM"}, "Technical Analysis: VWAP": {
+		"2023-09-08 19:55": {"VWAP": "147.5820"}, "2023-09-08 19:50": {"VWAP": "147.5815"}}}`}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	vwap, err := c.GetVWAP(models.IndicatorParams{Symbol: "IBM", Interval: models.Interval5Min, TimePeriod: 14, SeriesType: models.SeriesTypeClose})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "VWAP", "symbol": "IBM", "interval": "5min"}, "time_period", "series_type")
+	if len(vwap.IndicatorValues) != 2 || vwap.IndicatorValues[1].Values["VWAP"] != 147.582 {
+		t.Errorf("unexpected values %+v", vwap.IndicatorValues)
+	}
+
+	if _, err := c.GetVWAP(models.IndicatorParams{Symbol: "IBM", Interval: models.IntervalDaily}); err == nil {
+		t.Error("daily VWAP succeeded, want an error")
+	}
+}
+
+func TestMACDQuery(t *testing.T) {
+	recorder := &queryRecorder{body: `{"Meta Data": {"1: Symbol": "IBM"}, "Technical Analysis: MACD": {
+		"2023-09-08 16:00": {"MACD_Signal": "0.4169", "MACD": "0.5212", "MACD_Hist": "0.1043"}}}`}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	macd, err := c.GetMACD(models.IndicatorParams{
+		Symbol:       "IBM",
+		Interval:     models.Interval60Min,
+		SeriesType:   models.SeriesTypeOpen,
+		FastPeriod:   10,
+		SlowPeriod:   21,
+		SignalPeriod: 7,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{
+		"function":     "MACD",
+		"interval":     "60min",
+		"series_type":  "open",
+		"fastperiod":   "10",
+		"slowperiod":   "21",
+		"signalperiod": "7",
+	}, "fastlimit", "slowlimit")
+	if len(macd.IndicatorValues) != 1 {
+		t.Fatalf("got %d values, want 1", len(macd.IndicatorValues))
+	}
+	if values := macd.IndicatorValues[0].Values; values["MACD"] != 0.5212 || values["MACD_Signal"] != 0.4169 || values["MACD_Hist"] != 0.1043 {
+		t.Errorf("unexpected values %v", values)
+	}
+}
+
+func TestSTOCHQuery(t *testing.T) {
+	recorder := &queryRecorder{body: `{"Meta Data": {"1: Symbol": "IBM"}, "Technical Analysis: STOCH": {
+		"2023-09-08 16:00": {"SlowK": "61.7282", "SlowD": "58.6010"}}}`}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	stoch, err := c.GetSTOCH(models.IndicatorParams{
+		Symbol:      "IBM",
+		Interval:    models.Interval60Min,
+		FastKPeriod: 9,
+		SlowKPeriod: 4,
+		SlowDPeriod: 5,
+		SlowKMAType: 1,
+		SlowDMAType: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{
+		"function":    "STOCH",
+		"interval":    "60min",
+		"fastkperiod": "9",
+		"slowkperiod": "4",
+		"slowdperiod": "5",
+		"slowkmatype": "1",
+		"slowdmatype": "2",
+	}, "series_type", "fastdperiod", "fastdmatype")
+	if len(stoch.IndicatorValues) != 1 {
+		t.Fatalf("got %d values, want 1", len(stoch.IndicatorValues))
+	}
+	if values := stoch.IndicatorValues[0].Values; values["SlowK"] != 61.7282 || values["SlowD"] != 58.601 {
+		t.Errorf("unexpected values %v", values)
+	}
+}
+
+func TestSymbolNormalized(t *testing.T) {
+	rec := &queryRecorder{body: dailyBody}
+	c := newTestClient(t, rec.ServeHTTP)
+
+	if _, err := c.GetDaily(models.TimeSeriesParams{Symbol: " brk.a "}); err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, rec.Query(), map[string]string{"symbol": "BRK.A"})
+}
+
+func TestSymbolInvalid(t *testing.T) {
+	rec := &queryRecorder{body: dailyBody}
+	c := newTestClient(t, rec.ServeHTTP)
+
+	for _, symbol := range []string{"", "  ", "BRK A"} {
+		if _, err := c.GetDaily(models.TimeSeriesParams{Symbol: symbol}); err == nil {
+			t.Errorf("symbol %q: expected an error", symbol)
+		}
+	}
+	if rec.Query() != nil {
+		t.Errorf("request sent for an invalid symbol: %v", rec.Query())
+	}
+}
+
+func TestCSVQuote(t *testing.T) {
+	recorder := &queryRecorder{body: "symbol,open,high,low,price,volume,latestDay,previousClose,change,changePercent\n" +
+		"IBM,147.49,148.38,146.9,147.68,2958765,2023-09-08,147.52,0.16,0.1085%\n"}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	quote, err := c.GetQuoteEndpoint(models.TimeSeriesParams{Symbol: "IBM", DataType: models.DataTypeCSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "GLOBAL_QUOTE", "datatype": "csv"})
+	if quote.Symbol != "IBM" || quote.Price != 147.68 {
+		t.Errorf("unexpected quote %+v", quote)
+	}
+}
+
+func TestCSVIndicator(t *testing.T) {
+	recorder := &queryRecorder{body: "time,RSI\n2023-09-08,60.1\n2023-09-07,58.2\n"}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	rsi, err := c.GetRSI(models.IndicatorParams{
+		Symbol:     "IBM",
+		Interval:   models.IntervalDaily,
+		TimePeriod: 14,
+		SeriesType: models.SeriesTypeClose,
+		DataType:   models.DataTypeCSV,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "RSI", "datatype": "csv"})
+	if len(rsi.IndicatorValues) != 2 || rsi.IndicatorValues[1].Values["RSI"] != 60.1 {
+		t.Errorf("unexpected values %+v", rsi.IndicatorValues)
+	}
+}
+
+func TestNewsSentimentQuery(t *testing.T) {
+	recorder := &queryRecorder{body: string(readFixture(t, "news.json"))}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	// Times are sent in UTC, 16:30 in New York being 20:30 UTC in September
+	newYork := time.FixedZone("EDT", -4*60*60)
+	news, err := c.GetNewsSentiment(models.NewsParams{
+		Tickers:  []string{"IBM", "CRYPTO:BTC"},
+		Topics:   []string{"earnings", "technology"},
+		TimeFrom: time.Date(2023, 9, 8, 16, 30, 0, 0, newYork),
+		Sort:     models.NewsSortEarliest,
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{
+		"function":  "NEWS_SENTIMENT",
+		"tickers":   "IBM,CRYPTO:BTC",
+		"topics":    "earnings,technology",
+		"time_from": "20230908T2030",
+		"sort":      "EARLIEST",
+		"limit":     "10",
+	}, "time_to")
+
+	if len(news.Feed) != 1 {
+		t.Fatalf("got %d articles, want 1", len(news.Feed))
+	}
+	article := news.Feed[0]
+	if article.Title != "IBM Announces Quarterly Dividend" || article.OverallSentimentScore != 0.216 {
+		t.Errorf("unexpected article %+v", article)
+	}
+	if !article.TimePublished.Equal(time.Date(2023, 9, 8, 16, 15, 0, 0, time.UTC)) {
+		t.Errorf("published at %v, want 2023-09-08 16:15:00 UTC", article.TimePublished)
+	}
+	if len(article.Topics) != 2 || article.Topics[1].Relevance != 1 {
+		t.Errorf("unexpected topics %+v", article.Topics)
+	}
+	if len(article.TickerSentiment) != 1 || article.TickerSentiment[0].Ticker != "IBM" || article.TickerSentiment[0].SentimentScore != 0.31 {
+		t.Errorf("unexpected ticker sentiment %+v", article.TickerSentiment)
+	}
+}
+
+func TestNewsSentimentInvalidLimit(t *testing.T) {
+	recorder := &queryRecorder{body: `{"feed": []}`}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	if _, err := c.GetNewsSentiment(models.NewsParams{Limit: models.MaxNewsLimit + 1}); err == nil {
+		t.Fatal("expected an error for a limit above MaxNewsLimit")
+	}
+	if recorder.Query() != nil {
+		t.Errorf("request sent for an invalid limit: %v", recorder.Query())
+	}
+}
+
+func TestCryptoMarketQuery(t *testing.T) {
+	recorder := &queryRecorder{body: cryptoBody}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	crypto, err := c.GetCryptoDaily(models.CryptoParams{Symbol: "btc", Market: " usd "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{
+		"function": "DIGITAL_CURRENCY_DAILY",
+		"symbol":   "BTC",
+		"market":   "USD",
+	})
+	if len(crypto.TimeSeries) != 1 || crypto.TimeSeries[0].Close != 25280.73 {
+		t.Errorf("unexpected series %+v", crypto.TimeSeries)
+	}
+
+	recorder = &queryRecorder{body: cryptoBody}
+	c = newTestClient(t, recorder.ServeHTTP)
+	if _, err := c.GetCryptoDaily(models.CryptoParams{Symbol: "BTC", Market: "US$"}); err == nil {
+		t.Error("invalid market: expected an error")
+	}
+	if recorder.Query() != nil {
+		t.Errorf("request sent for an invalid market: %v", recorder.Query())
+	}
+}
+
+func TestInvalidIntervalNotSent(t *testing.T) {
+	recorder := &queryRecorder{body: dailyBody}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	if _, err := c.GetIntraday(models.TimeSeriesParams{Symbol: "IBM", Interval: "5mins"}); err == nil {
+		t.Error("intraday: expected an error for interval 5mins")
+	}
+	if _, err := c.GetRSI(models.IndicatorParams{Symbol: "IBM", Interval: "5mins", TimePeriod: 14}); err == nil {
+		t.Error("RSI: expected an error for interval 5mins")
+	}
+	if recorder.Query() != nil {
+		t.Errorf("request sent for an invalid interval: %v", recorder.Query())
+	}
+}
+
+func TestExchangeSuffixQuery(t *testing.T) {
+	tests := []struct {
+		symbol, want string
+	}{
+		{"tsco", "TSCO.LON"},
+		{"BA.LSE", "BA.LSE"},
+		{"BRK.B", "BRK.B.LON"},
+	}
+	for _, tt := range tests {
+		recorder := &queryRecorder{body: dailyBody}
+		c := newTestClient(t, recorder.ServeHTTP, WithExchangeSuffix("LON"))
+		if _, err := c.GetDaily(models.TimeSeriesParams{Symbol: tt.symbol}); err != nil {
+			t.Fatal(err)
+		}
+		checkQuery(t, recorder.Query(), map[string]string{"symbol": tt.want})
+	}
+
+	// Digital currencies are not listed on an exchange
+	recorder := &queryRecorder{body: cryptoBody}
+	c := newTestClient(t, recorder.ServeHTTP, WithExchangeSuffix("LON"))
+	if _, err := c.GetCryptoDaily(models.CryptoParams{Symbol: "BTC", Market: "USD"}); err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"symbol": "BTC"})
+}
+
+func TestOutputSizeQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		params models.TimeSeriesParams
+		want   string // empty when the parameter must be omitted
+	}{
+		{"unset", nil, models.TimeSeriesParams{Symbol: "IBM"}, ""},
+		{"default", []Option{WithDefaultOutputSize(models.OutputSizeFull)}, models.TimeSeriesParams{Symbol: "IBM"}, "full"},
+		{"per call", nil, models.TimeSeriesParams{Symbol: "IBM", OutputSize: models.OutputSizeFull}, "full"},
+		{"per call overrides default", []Option{WithDefaultOutputSize(models.OutputSizeFull)},
+			models.TimeSeriesParams{Symbol: "IBM", OutputSize: models.OutputSizeCompact}, "compact"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &queryRecorder{body: dailyBody}
+			c := newTestClient(t, recorder.ServeHTTP, tt.opts...)
+			if _, err := c.GetDaily(tt.params); err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				checkQuery(t, recorder.Query(), nil, "outputsize")
+				return
+			}
+			checkQuery(t, recorder.Query(), map[string]string{"outputsize": tt.want})
+		})
+	}
+}
+
+func TestCSVSeriesRejected(t *testing.T) {
+	recorder := &queryRecorder{body: "timestamp,open,high,low,close,volume\n2023-09-08,147.49,148.38,146.81,147.68,2805923\n"}
+	c := newTestClient(t, recorder.ServeHTTP)
+	params := models.TimeSeriesParams{Symbol: "IBM", DataType: models.DataTypeCSV}
+
+	if _, err := c.GetDaily(params); err == nil || !strings.Contains(err.Error(), "csv") {
+		t.Errorf("daily: err = %v, want an error naming csv", err)
+	}
+	if _, err := c.GetWeekly(params); err == nil {
+		t.Error("weekly: expected an error")
+	}
+	if _, err := c.GetMonthlyAdjusted(params); err == nil {
+		t.Error("monthly adjusted: expected an error")
+	}
+	if recorder.Query() != nil {
+		t.Errorf("request sent for an unsupported CSV series: %v", recorder.Query())
+	}
+}
+
+func TestHeaderAuth(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"client key", []Option{WithHeaderAuth("X-Api-Key")}, "test"},
+		{"key ring", []Option{WithHeaderAuth("X-Api-Key"), WithAPIKeys([]string{"key1", "key2"})}, "key1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header string
+			var query url.Values
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				header, query = r.Header.Get("X-Api-Key"), r.URL.Query()
+				w.Write([]byte(dailyBody))
+			}, tt.opts...)
+
+			if _, err := c.GetDaily(models.TimeSeriesParams{Symbol: "IBM"}); err != nil {
+				t.Fatal(err)
+			}
+			if header != tt.want {
+				t.Errorf("X-Api-Key = %q, want %q", header, tt.want)
+			}
+			checkQuery(t, query, map[string]string{"symbol": "IBM"}, "apikey")
+		})
+	}
+}
+
+func TestListingStatusQuery(t *testing.T) {
+	recorder := &queryRecorder{body: string(readFixture(t, "listing_status.csv"))}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	listings, err := c.GetListingStatus("2023-09-08", "delisted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "LISTING_STATUS", "date": "2023-09-08", "state": "delisted"})
+	if len(listings) != 5 || listings[2].Symbol != "IBM" || listings[2].Exchange != "NYSE" {
+		t.Errorf("unexpected listings %+v", listings)
+	}
+
+	// The defaults are left to the API
+	if _, err := c.GetListingStatus("", ""); err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "LISTING_STATUS"}, "date", "state")
+
+	if _, err := c.GetListingStatus("", "halted"); err == nil {
+		t.Error("invalid state succeeded, want an error")
+	}
+}
+
+func TestFXQuery(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		function string
+		call     func(c *Client, params models.FXParams) (*models.FXTimeSeries, error)
+		params   models.FXParams
+		want     map[string]string
+		absent   []string
+	}{
+		{"fx_intraday.json", "FX_INTRADAY", (*Client).GetFXIntraday,
+			models.FXParams{FromSymbol: "EUR", ToSymbol: "USD", Interval: "5min", OutputSize: models.OutputSizeFull},
+			map[string]string{"from_symbol": "EUR", "to_symbol": "USD", "interval": "5min", "outputsize": "full"}, nil},
+		{"fx_daily.json", "FX_DAILY", (*Client).GetFXDaily,
+			models.FXParams{FromSymbol: "EUR", ToSymbol: "USD"},
+			map[string]string{"from_symbol": "EUR", "to_symbol": "USD"}, []string{"interval", "outputsize"}},
+		{"fx_daily.json", "FX_WEEKLY", (*Client).GetFXWeekly, models.FXParams{FromSymbol: "EUR", ToSymbol: "USD"}, nil, nil},
+		{"fx_daily.json", "FX_MONTHLY", (*Client).GetFXMonthly, models.FXParams{FromSymbol: "EUR", ToSymbol: "USD"}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			recorder := &queryRecorder{body: string(readFixture(t, tt.fixture))}
+			c := newTestClient(t, recorder.ServeHTTP)
+
+			fx, err := tt.call(c, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{"function": tt.function}
+			for key, value := range tt.want {
+				want[key] = value
+			}
+			checkQuery(t, recorder.Query(), want, tt.absent...)
+			if len(fx.TimeSeries) != 2 || fx.MetaData.FromSymbol != "EUR" {
+				t.Errorf("unexpected series %+v", fx)
+			}
+		})
+	}
+}
+
+func TestTopGainersLosersQuery(t *testing.T) {
+	recorder := &queryRecorder{body: string(readFixture(t, "top_gainers_losers.json"))}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	movers, err := c.GetTopGainersLosers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "TOP_GAINERS_LOSERS", "apikey": "test"}, "symbol")
+	if len(movers.TopGainers) != 2 || movers.TopGainers[0].Ticker != "NXU" {
+		t.Errorf("unexpected gainers %+v", movers.TopGainers)
+	}
+}
+
+func TestCommodityQuery(t *testing.T) {
+	tests := []struct {
+		function string
+		call     func(c *Client, interval string) (*models.CommoditySeries, error)
+	}{
+		{"WTI", (*Client).GetWTI},
+		{"BRENT", (*Client).GetBrent},
+		{"NATURAL_GAS", (*Client).GetNaturalGas},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			recorder := &queryRecorder{body: string(readFixture(t, "wti.json"))}
+			c := newTestClient(t, recorder.ServeHTTP)
+
+			series, err := tt.call(c, "monthly")
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkQuery(t, recorder.Query(), map[string]string{"function": tt.function, "interval": "monthly"})
+			if len(series.Data) != 3 || series.Data[2].Value != 81.39 {
+				t.Errorf("unexpected data %v", series.Data)
+			}
+
+			if _, err := tt.call(c, ""); err != nil {
+				t.Fatal(err)
+			}
+			checkQuery(t, recorder.Query(), map[string]string{"function": tt.function}, "interval")
+		})
+	}
+}
+
+func TestEconomicIndicatorQuery(t *testing.T) {
+	tests := []struct {
+		function string
+		call     func(c *Client) (*models.EconomicSeries, error)
+		want     map[string]string
+		absent   []string
+	}{
+		{"REAL_GDP", func(c *Client) (*models.EconomicSeries, error) { return c.GetRealGDP("annual") },
+			map[string]string{"interval": "annual"}, []string{"maturity"}},
+		{"CPI", func(c *Client) (*models.EconomicSeries, error) { return c.GetCPI("") },
+			nil, []string{"interval", "maturity"}},
+		{"INFLATION", (*Client).GetInflation, nil, []string{"interval", "maturity"}},
+		{"UNEMPLOYMENT", (*Client).GetUnemployment, nil, []string{"interval", "maturity"}},
+		{"FEDERAL_FUNDS_RATE", func(c *Client) (*models.EconomicSeries, error) { return c.GetFederalFundsRate("weekly") },
+			map[string]string{"interval": "weekly"}, []string{"maturity"}},
+		{"TREASURY_YIELD", func(c *Client) (*models.EconomicSeries, error) { return c.GetTreasuryYield("daily", "10year") },
+			map[string]string{"interval": "daily", "maturity": "10year"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			recorder := &queryRecorder{body: string(readFixture(t, "treasury_yield.json"))}
+			c := newTestClient(t, recorder.ServeHTTP)
+
+			series, err := tt.call(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{"function": tt.function}
+			for key, value := range tt.want {
+				want[key] = value
+			}
+			checkQuery(t, recorder.Query(), want, tt.absent...)
+			if len(series.Data) != 3 || series.Unit != "percent" {
+				t.Errorf("unexpected series %+v", series)
+			}
+		})
+	}
+}
+
+func TestFixedWindowAnalyticsQuery(t *testing.T) {
+	recorder := &queryRecorder{body: string(readFixture(t, "analytics_fixed_window.json"))}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	analytics, err := c.GetFixedWindowAnalytics(models.AnalyticsParams{
+		Symbols:      []string{"AAPL", "MSFT", "IBM"},
+		Range:        []string{"2023-07-01", "2023-08-31"},
+		Interval:     "DAILY",
+		Calculations: []string{"MEAN", "STDDEV", "CORRELATION"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := recorder.Query()
+	checkQuery(t, query, map[string]string{
+		"function":     "ANALYTICS_FIXED_WINDOW",
+		"SYMBOLS":      "AAPL,MSFT,IBM",
+		"INTERVAL":     "DAILY",
+		"CALCULATIONS": "MEAN,STDDEV,CORRELATION",
+	}, "OHLC")
+	// The range is sent as two RANGE parameters rather than a joined one
+	if got := query["RANGE"]; len(got) != 2 || got[0] != "2023-07-01" || got[1] != "2023-08-31" {
+		t.Errorf("RANGE = %v, want [2023-07-01 2023-08-31]", got)
+	}
+	if _, ok := analytics.Correlation(); !ok || len(analytics.Statistics) != 2 {
+		t.Errorf("unexpected result %+v", analytics)
+	}
+}
+
+func TestMarketStatusQuery(t *testing.T) {
+	recorder := &queryRecorder{body: string(readFixture(t, "market_status.json"))}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	status, err := c.GetMarketStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "MARKET_STATUS", "apikey": "test"}, "symbol")
+	if len(status.Markets) != 3 || !status.IsOpen("United States") {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestCorporateActionsQuery(t *testing.T) {
+	t.Run("splits", func(t *testing.T) {
+		recorder := &queryRecorder{body: string(readFixture(t, "splits.json"))}
+		c := newTestClient(t, recorder.ServeHTTP)
+
+		splits, err := c.GetSplits("IBM")
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkQuery(t, recorder.Query(), map[string]string{"function": "SPLITS", "symbol": "IBM"})
+		if len(splits) != 3 || splits[0].SplitFactor != 4 {
+			t.Errorf("unexpected splits %+v", splits)
+		}
+	})
+
+	t.Run("dividends", func(t *testing.T) {
+		recorder := &queryRecorder{body: string(readFixture(t, "dividends.json"))}
+		c := newTestClient(t, recorder.ServeHTTP)
+
+		dividends, err := c.GetDividends("IBM")
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkQuery(t, recorder.Query(), map[string]string{"function": "DIVIDENDS", "symbol": "IBM"})
+		if len(dividends) != 2 || dividends[1].Amount != 1.66 {
+			t.Errorf("unexpected dividends %+v", dividends)
+		}
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		})
+		if _, err := c.GetSplits("NEWCO"); !errors.Is(err, models.ErrNoData) {
+			t.Errorf("err = %v, want ErrNoData", err)
+		}
+	})
+}
+
+func TestOptionsQuery(t *testing.T) {
+	recorder := &queryRecorder{body: string(readFixture(t, "historical_options.json"))}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	chain, err := c.GetHistoricalOptions("IBM", "2023-09-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "HISTORICAL_OPTIONS", "symbol": "IBM", "date": "2023-09-08"}, "require_greeks")
+	if len(chain.Contracts) != 3 || chain.Contracts[0].Strike != 145 {
+		t.Errorf("unexpected contracts %+v", chain.Contracts)
+	}
+
+	// The date defaults to the previous session
+	if _, err := c.GetHistoricalOptions("IBM", ""); err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "HISTORICAL_OPTIONS"}, "date")
+
+	if _, err := c.GetRealtimeOptions("IBM"); err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "REALTIME_OPTIONS", "symbol": "IBM", "require_greeks": "true"}, "date")
+}
+
+func TestCryptoRatingQuery(t *testing.T) {
+	recorder := &queryRecorder{body: string(readFixture(t, "crypto_rating.json"))}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	rating, err := c.GetCryptoRating("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQuery(t, recorder.Query(), map[string]string{"function": "CRYPTO_RATING", "symbol": "BTC"})
+	if rating.Symbol != "BTC" || rating.FCASRating != "Superb" {
+		t.Errorf("unexpected rating %+v", rating)
+	}
+
+	// A symbol without a rating has no rating section
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	if _, err := c.GetCryptoRating("NEWCOIN"); !errors.Is(err, models.ErrNoData) {
+		t.Errorf("err = %v, want ErrNoData", err)
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+import "sync"
+
+// rawCapture holds a copy of the last response body. It is safe for concurrent use.
+type rawCapture struct {
+	mu   sync.Mutex
+	last []byte
+}
+
+// Store replaces the captured body with a copy of body.
+func (r *rawCapture) Store(body []byte) {
+	body = append([]byte(nil), body...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = body
+}
+
+// Load returns a copy of the captured body, or nil if there is none yet.
+func (r *rawCapture) Load() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.last == nil {
+		return nil
+	}
+	return append([]byte(nil), r.last...)
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/masonJamesWheeler/alpha-vantage-go-wrapper/models"
+)
+
+// StreamQuote polls GLOBAL_QUOTE for symbol every interval, starting immediately, and sends
+// each quote on the first channel until ctx is canceled, after which both channels are closed.
+// A failed poll sends its error on the second channel and the stream carries on with the next
+// tick. The client's rate limit, if set, is respected, so polls may be spaced further apart.
+//
+// The quote channel is unbuffered and the error channel buffers a single error, which carries
+// an invalid interval back before any polling. Both must be received from, otherwise polling
+// stalls until the value is taken or ctx is canceled.
+func (c *Client) StreamQuote(ctx context.Context, symbol string, every time.Duration) (<-chan models.Quote, <-chan error) {
+	quotes := make(chan models.Quote)
+	errs := make(chan error, 1)
+
+	if every <= 0 {
+		errs <- fmt.Errorf("invalid polling interval %s: must be positive", every)
+		close(quotes)
+		close(errs)
+		return quotes, errs
+	}
+
+	go func() {
+		defer close(quotes)
+		defer close(errs)
+
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		for {
+			quote, err := c.getQuote(ctx, models.TimeSeriesParams{Symbol: symbol})
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err != nil {
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+					return
+				}
+			} else {
+				select {
+				case quotes <- quote:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return quotes, errs
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const quoteBody = `{"Global Quote": {"01. symbol": "IBM", "05. price": "147.6800"}}`
+
+// waitClosed fails the test unless ch is closed within a second, draining any values first.
+func waitClosed[T any](t *testing.T, name string, ch <-chan T) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("%s channel not closed", name)
+		}
+	}
+}
+
+func TestStreamQuote(t *testing.T) {
+	recorder := &queryRecorder{body: quoteBody}
+	c := newTestClient(t, recorder.ServeHTTP)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	quotes, errs := c.StreamQuote(ctx, "IBM", time.Millisecond)
+	for i := 0; i < 3; i++ {
+		select {
+		case quote := <-quotes:
+			if quote.Symbol != "IBM" || quote.Price != 147.68 {
+				t.Fatalf("unexpected quote %+v", quote)
+			}
+		case err := <-errs:
+			t.Fatal(err)
+		case <-time.After(time.Second):
+			t.Fatal("no quote received")
+		}
+	}
+
+	// Cancelling stops the polling and closes both channels
+	cancel()
+	waitClosed(t, "quote", quotes)
+	waitClosed(t, "error", errs)
+}
+
+func TestStreamQuoteStopsWhileBlocked(t *testing.T) {
+	recorder := &queryRecorder{body: quoteBody}
+	c := newTestClient(t, recorder.ServeHTTP)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Nothing is received, so the stream blocks sending its first quote until cancelled
+	quotes, errs := c.StreamQuote(ctx, "IBM", time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	waitClosed(t, "quote", quotes)
+	waitClosed(t, "error", errs)
+}
+
+func TestStreamQuoteInvalidInterval(t *testing.T) {
+	recorder := &queryRecorder{body: quoteBody}
+	c := newTestClient(t, recorder.ServeHTTP)
+
+	quotes, errs := c.StreamQuote(context.Background(), "IBM", 0)
+	if err := <-errs; err == nil {
+		t.Fatal("expected an error for a zero interval")
+	}
+	waitClosed(t, "quote", quotes)
+	waitClosed(t, "error", errs)
+	if recorder.Query() != nil {
+		t.Errorf("request sent for an invalid interval: %v", recorder.Query())
+	}
+}
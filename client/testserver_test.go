@@ -0,0 +1,77 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// newTestClient starts an httptest.Server backed by handler and returns a Client pointed at
+// it. The server is closed when the test ends.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	opts = append([]Option{WithBaseURL(srv.URL)}, opts...)
+	return NewClient("test", opts...)
+}
+
+// readFixture returns the contents of the named file of the repository's testdata directory.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// fixtureHandler returns a handler that answers every request with the named fixture.
+func fixtureHandler(t *testing.T, name string) http.HandlerFunc {
+	body := readFixture(t, name)
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+// queryRecorder answers every request with body and records the query of the last one.
+type queryRecorder struct {
+	mu    sync.Mutex
+	body  string
+	query url.Values
+}
+
+func (r *queryRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.query = req.URL.Query()
+	r.mu.Unlock()
+	w.Write([]byte(r.body))
+}
+
+// Query returns the query of the last request.
+func (r *queryRecorder) Query() url.Values {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.query
+}
+
+// checkQuery reports the parameters of want that query lacks or holds with another value,
+// and the parameters of absent that query holds.
+func checkQuery(t *testing.T, query url.Values, want map[string]string, absent ...string) {
+	t.Helper()
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	for _, key := range absent {
+		if query.Has(key) {
+			t.Errorf("%s = %q, want it omitted", key, query.Get(key))
+		}
+	}
+}
@@ -0,0 +1,588 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage data locally.
+//
+// This file contains helpers that compute indicators and statistics client-side
+// from data already fetched from the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// PriceField selects which price of a bar a computation uses.
+type PriceField string
+
+// Price fields of an OHLCV bar.
+const (
+	PriceOpen  PriceField = "open"
+	PriceHigh  PriceField = "high"
+	PriceLow   PriceField = "low"
+	PriceClose PriceField = "close"
+)
+
+// value returns the price selected by the field from the bar.
+func (f PriceField) value(bar OHLCV) (float64, error) {
+	switch f {
+	case PriceOpen:
+		return bar.Open, nil
+	case PriceHigh:
+		return bar.High, nil
+	case PriceLow:
+		return bar.Low, nil
+	case PriceClose:
+		return bar.Close, nil
+	}
+	return 0, fmt.Errorf("invalid price field %q", f)
+}
+
+// SMA computes the simple moving average of the selected price over period bars.
+// The result is aligned to the timestamps of the series, omitting the first period-1 bars,
+// and each value is stored under the "SMA" key like the API's SMA response.
+func SMA(series []OHLCV, period int, field PriceField) ([]IndicatorValue, error) {
+	prices, err := movingAverageInput(series, period, field)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]IndicatorValue, 0, len(prices)-period+1)
+	sum := 0.0
+	for i, price := range prices {
+		sum += price
+		if i >= period {
+			sum -= prices[i-period]
+		}
+		if i >= period-1 {
+			values = append(values, IndicatorValue{
+				Timestamp: series[i].Timestamp,
+				Values:    map[string]float64{"SMA": sum / float64(period)},
+			})
+		}
+	}
+
+	return values, nil
+}
+
+// EMA computes the exponential moving average of the selected price over period bars.
+// The average is seeded with the SMA of the first period bars, so like SMA the first
+// period-1 bars are omitted. Each value is stored under the "EMA" key.
+func EMA(series []OHLCV, period int, field PriceField) ([]IndicatorValue, error) {
+	prices, err := movingAverageInput(series, period, field)
+	if err != nil {
+		return nil, err
+	}
+
+	alpha := 2 / float64(period+1)
+	values := make([]IndicatorValue, 0, len(prices)-period+1)
+
+	ema := 0.0
+	for _, price := range prices[:period] {
+		ema += price
+	}
+	ema /= float64(period)
+
+	for i := period - 1; i < len(prices); i++ {
+		if i >= period {
+			ema = alpha*prices[i] + (1-alpha)*ema
+		}
+		values = append(values, IndicatorValue{
+			Timestamp: series[i].Timestamp,
+			Values:    map[string]float64{"EMA": ema},
+		})
+	}
+
+	return values, nil
+}
+
+// movingAverageInput validates the arguments of the moving averages and extracts the prices.
+func movingAverageInput(series []OHLCV, period int, field PriceField) ([]float64, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("period must be positive, got %d", period)
+	}
+	if len(series) < period {
+		return nil, fmt.Errorf("insufficient data: %d bars for a period of %d", len(series), period)
+	}
+
+	prices := make([]float64, len(series))
+	for i, bar := range series {
+		price, err := field.value(bar)
+		if err != nil {
+			return nil, err
+		}
+		prices[i] = price
+	}
+	return prices, nil
+}
+
+// simpleReturns returns the arithmetic returns between consecutive prices, where
+// returns[i] is the move from prices[i] to prices[i+1]. It is empty for fewer than two prices.
+// A move from a zero price has no defined return and is NaN.
+func simpleReturns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return []float64{}
+	}
+
+	returns := make([]float64, len(prices)-1)
+	for i := range returns {
+		if prices[i] == 0 {
+			returns[i] = math.NaN()
+			continue
+		}
+		returns[i] = prices[i+1]/prices[i] - 1
+	}
+	return returns
+}
+
+// logReturns is simpleReturns with log returns, ln(prices[i+1]/prices[i]). A move from a
+// zero price is NaN, as in simpleReturns.
+func logReturns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return []float64{}
+	}
+
+	returns := make([]float64, len(prices)-1)
+	for i := range returns {
+		if prices[i] == 0 {
+			returns[i] = math.NaN()
+			continue
+		}
+		returns[i] = math.Log(prices[i+1] / prices[i])
+	}
+	return returns
+}
+
+// closes extracts the close prices of the series.
+func closes(series []OHLCV) []float64 {
+	prices := make([]float64, len(series))
+	for i, v := range series {
+		prices[i] = v.Close
+	}
+	return prices
+}
+
+// adjustedCloses extracts the adjusted close prices of the series.
+func adjustedCloses(series []AdjustedOHLCV) []float64 {
+	prices := make([]float64, len(series))
+	for i, v := range series {
+		prices[i] = v.AdjustedClose
+	}
+	return prices
+}
+
+// Returns computes the simple returns between consecutive closes. The result is one shorter
+// than the series: Returns()[i] is the move from bar i to bar i+1, or NaN if close i is zero.
+// It is empty for fewer than two bars.
+func (t TimeSeriesDaily) Returns() []float64 {
+	return simpleReturns(closes(t.TimeSeries))
+}
+
+// LogReturns computes the log returns between consecutive closes, aligned like Returns.
+func (t TimeSeriesDaily) LogReturns() []float64 {
+	return logReturns(closes(t.TimeSeries))
+}
+
+// AdjustedReturns computes the simple returns between consecutive adjusted closes, so that
+// dividends and splits are accounted for. It is aligned like TimeSeriesDaily.Returns.
+func (t TimeSeriesDailyAdjusted) AdjustedReturns() []float64 {
+	return simpleReturns(adjustedCloses(t.TimeSeries))
+}
+
+// AdjustedLogReturns computes the log returns between consecutive adjusted closes.
+func (t TimeSeriesDailyAdjusted) AdjustedLogReturns() []float64 {
+	return logReturns(adjustedCloses(t.TimeSeries))
+}
+
+// AdjustedReturns computes the simple returns between consecutive adjusted closes.
+func (t TimeSeriesWeeklyAdjusted) AdjustedReturns() []float64 {
+	return simpleReturns(adjustedCloses(t.TimeSeries))
+}
+
+// AdjustedLogReturns computes the log returns between consecutive adjusted closes.
+func (t TimeSeriesWeeklyAdjusted) AdjustedLogReturns() []float64 {
+	return logReturns(adjustedCloses(t.TimeSeries))
+}
+
+// AdjustedReturns computes the simple returns between consecutive adjusted closes.
+func (t TimeSeriesMonthlyAdjusted) AdjustedReturns() []float64 {
+	return simpleReturns(adjustedCloses(t.TimeSeries))
+}
+
+// AdjustedLogReturns computes the log returns between consecutive adjusted closes.
+func (t TimeSeriesMonthlyAdjusted) AdjustedLogReturns() []float64 {
+	return logReturns(adjustedCloses(t.TimeSeries))
+}
+
+// XY is a point for plotting, with the timestamp as Unix seconds on X. It has the layout of
+// gonum's plotter.XY, so a []XY is adapted to plotter.XYs by copying the points over.
+type XY struct {
+	X, Y float64
+}
+
+// xys pairs the timestamps of the bars with the prices selected by field. It returns nil for
+// an invalid field.
+func xys(series []OHLCV, field PriceField) []XY {
+	points := make([]XY, len(series))
+	for i, bar := range series {
+		price, err := field.value(bar)
+		if err != nil {
+			return nil
+		}
+		points[i] = XY{X: float64(bar.Timestamp.Unix()), Y: price}
+	}
+	return points
+}
+
+// XYs returns the selected price of every bar against its timestamp, for plotting.
+// It returns nil for an invalid field.
+func (t TimeSeriesDaily) XYs(field PriceField) []XY {
+	return xys(t.TimeSeries, field)
+}
+
+// XYs returns the selected unadjusted price of every bar against its timestamp, like TimeSeriesDaily.XYs.
+func (t TimeSeriesDailyAdjusted) XYs(field PriceField) []XY {
+	return xys(unadjusted(t.TimeSeries), field)
+}
+
+// XYs returns the selected price of every bar against its timestamp, like TimeSeriesDaily.XYs.
+func (t TimeSeriesIntraday) XYs(field PriceField) []XY {
+	return xys(t.TimeSeries, field)
+}
+
+// XYs returns the named output, e.g. "RSI", against its timestamps, for plotting. Timestamps
+// without that output are skipped, as in Series.
+func (i IndicatorResponse) XYs(name string) []XY {
+	points, _ := i.Series(name)
+	xy := make([]XY, len(points))
+	for k, p := range points {
+		xy[k] = XY{X: float64(p.Time.Unix()), Y: p.Value}
+	}
+	return xy
+}
+
+// Align inner-joins the closes of two daily series on timestamp: it returns the timestamps
+// present in both, ascending, with the close of each series at those timestamps. Both series
+// are expected in ascending order, as returned by the unmarshaler, and are merged in one pass.
+func Align(a, b TimeSeriesDaily) (timestamps []time.Time, aClose, bClose []float64) {
+	timestamps, closes := AlignN(a, b)
+	return timestamps, closes[0], closes[1]
+}
+
+// AlignN is Align for any number of series: closes[i] holds the closes of series[i] at the
+// returned timestamps, which are those present in every series.
+func AlignN(series ...TimeSeriesDaily) (timestamps []time.Time, closes [][]float64) {
+	closes = make([][]float64, len(series))
+	if len(series) == 0 {
+		return nil, closes
+	}
+
+	pos := make([]int, len(series))
+	for {
+		// The latest of the current timestamps is the earliest one all series can share
+		var latest time.Time
+		for i, s := range series {
+			if pos[i] == len(s.TimeSeries) {
+				return timestamps, closes
+			}
+			if ts := s.TimeSeries[pos[i]].Timestamp; i == 0 || ts.After(latest) {
+				latest = ts
+			}
+		}
+
+		matched := true
+		for i, s := range series {
+			for pos[i] < len(s.TimeSeries) && s.TimeSeries[pos[i]].Timestamp.Before(latest) {
+				pos[i]++
+			}
+			if pos[i] == len(s.TimeSeries) {
+				return timestamps, closes
+			}
+			if !s.TimeSeries[pos[i]].Timestamp.Equal(latest) {
+				matched = false
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		timestamps = append(timestamps, latest)
+		for i, s := range series {
+			closes[i] = append(closes[i], s.TimeSeries[pos[i]].Close)
+			pos[i]++
+		}
+	}
+}
+
+// RollingCorrelation computes the Pearson correlation of a and b over each window of window
+// consecutive values, e.g. returns aligned with Align. The result has one value per full
+// window: value i covers a[i:i+window]. A window in which either input is constant has no
+// defined correlation and yields NaN.
+func RollingCorrelation(a, b []float64, window int) ([]float64, error) {
+	if err := pairedInput(a, b); err != nil {
+		return nil, err
+	}
+	if window < 2 {
+		return nil, fmt.Errorf("window must be at least 2, got %d", window)
+	}
+	if len(a) < window {
+		return nil, fmt.Errorf("insufficient data: %d values for a window of %d", len(a), window)
+	}
+
+	correlations := make([]float64, 0, len(a)-window+1)
+	for i := 0; i+window <= len(a); i++ {
+		cov, varA, varB := covariance(a[i:i+window], b[i:i+window])
+		if varA == 0 || varB == 0 {
+			correlations = append(correlations, math.NaN())
+			continue
+		}
+		correlations = append(correlations, cov/math.Sqrt(varA*varB))
+	}
+	return correlations, nil
+}
+
+// Beta computes the beta of asset against market, cov(asset, market) / var(market), from two
+// aligned return series such as the Returns of two series aligned with Align.
+func Beta(asset, market []float64) (float64, error) {
+	if err := pairedInput(asset, market); err != nil {
+		return 0, err
+	}
+	if len(asset) < 2 {
+		return 0, fmt.Errorf("insufficient data: %d values, at least 2 are needed", len(asset))
+	}
+
+	cov, _, varMarket := covariance(asset, market)
+	if varMarket == 0 {
+		return 0, fmt.Errorf("market returns have zero variance")
+	}
+	return cov / varMarket, nil
+}
+
+// pairedInput checks that two series to be compared value by value have the same length.
+func pairedInput(a, b []float64) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("mismatched lengths: %d and %d values", len(a), len(b))
+	}
+	return nil
+}
+
+// covariance returns the population covariance of a and b and the variance of each.
+func covariance(a, b []float64) (cov, varA, varB float64) {
+	n := float64(len(a))
+	meanA, meanB := 0.0, 0.0
+	for i := range a {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= n
+	meanB /= n
+
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+	return cov / n, varA / n, varB / n
+}
+
+// SignalType is the direction of a Signal.
+type SignalType string
+
+// Kinds of Signal.
+const (
+	SignalBullish SignalType = "bullish" // the fast line crossed above the slow line
+	SignalBearish SignalType = "bearish" // the fast line crossed below the slow line
+)
+
+// Signal marks the bar on which a crossover occurred.
+type Signal struct {
+	Timestamp time.Time
+	Type      SignalType
+}
+
+// Crossovers emits a Signal on every bar where the fast line, e.g. a short EMA, crosses the
+// slow line, e.g. a long SMA, as computed by SMA and EMA. The lines are inner-joined on
+// timestamp, so they need not start on the same bar, and are expected in ascending order.
+// Each value must hold a single output; others are skipped. A line touching the other
+// without crossing emits nothing, and the first bar never emits as there is no crossing yet.
+func Crossovers(fast, slow []IndicatorValue) []Signal {
+	var signals []Signal
+	prevSign := 0
+	i, j := 0, 0
+	for i < len(fast) && j < len(slow) {
+		switch {
+		case fast[i].Timestamp.Before(slow[j].Timestamp):
+			i++
+			continue
+		case slow[j].Timestamp.Before(fast[i].Timestamp):
+			j++
+			continue
+		}
+
+		f, okFast := singleValue(fast[i])
+		s, okSlow := singleValue(slow[j])
+		timestamp := fast[i].Timestamp
+		i++
+		j++
+		if !okFast || !okSlow {
+			continue
+		}
+
+		sign := 0
+		switch {
+		case f > s:
+			sign = 1
+		case f < s:
+			sign = -1
+		}
+		if sign == 0 {
+			// Equal lines have not crossed yet; the side they leave to decides
+			continue
+		}
+
+		if prevSign != 0 && sign != prevSign {
+			signalType := SignalBullish
+			if sign < 0 {
+				signalType = SignalBearish
+			}
+			signals = append(signals, Signal{Timestamp: timestamp, Type: signalType})
+		}
+		prevSign = sign
+	}
+	return signals
+}
+
+// singleValue returns the value of v if it holds exactly one output, like SMA and EMA do.
+func singleValue(v IndicatorValue) (float64, bool) {
+	if len(v.Values) != 1 {
+		return 0, false
+	}
+	for _, value := range v.Values {
+		return value, true
+	}
+	return 0, false
+}
+
+// SeriesStats summarizes one price column of a series.
+type SeriesStats struct {
+	Count   int
+	Min     float64
+	Max     float64
+	Mean    float64
+	StdDev  float64 // population standard deviation
+	MinTime time.Time
+	MaxTime time.Time // earliest timestamp of the maximum, like MinTime for the minimum
+}
+
+// summarize computes the SeriesStats of the bars' prices selected by field. It returns the
+// zero SeriesStats for an empty series or an invalid field.
+func summarize(series []OHLCV, field PriceField) SeriesStats {
+	var stats SeriesStats
+	sum, sumSquares := 0.0, 0.0
+	for i, bar := range series {
+		price, err := field.value(bar)
+		if err != nil {
+			return SeriesStats{}
+		}
+
+		if i == 0 || price < stats.Min {
+			stats.Min, stats.MinTime = price, bar.Timestamp
+		}
+		if i == 0 || price > stats.Max {
+			stats.Max, stats.MaxTime = price, bar.Timestamp
+		}
+		sum += price
+		sumSquares += price * price
+		stats.Count++
+	}
+
+	if stats.Count == 0 {
+		return stats
+	}
+	n := float64(stats.Count)
+	stats.Mean = sum / n
+	stats.StdDev = math.Sqrt(math.Max(sumSquares/n-stats.Mean*stats.Mean, 0))
+	return stats
+}
+
+// unadjusted extracts the plain OHLCV bars of an adjusted series.
+func unadjusted(series []AdjustedOHLCV) []OHLCV {
+	bars := make([]OHLCV, len(series))
+	for i, v := range series {
+		bars[i] = v.OHLCV
+	}
+	return bars
+}
+
+// Summary computes the minimum, maximum, mean and standard deviation of the selected price.
+// It returns the zero SeriesStats for an empty series or an invalid field.
+func (t TimeSeriesDaily) Summary(field PriceField) SeriesStats {
+	return summarize(t.TimeSeries, field)
+}
+
+// Summary computes the SeriesStats of the selected unadjusted price, like TimeSeriesDaily.Summary.
+func (t TimeSeriesDailyAdjusted) Summary(field PriceField) SeriesStats {
+	return summarize(unadjusted(t.TimeSeries), field)
+}
+
+// Summary computes the SeriesStats of the selected unadjusted price, like TimeSeriesDaily.Summary.
+func (t TimeSeriesWeeklyAdjusted) Summary(field PriceField) SeriesStats {
+	return summarize(unadjusted(t.TimeSeries), field)
+}
+
+// Summary computes the SeriesStats of the selected unadjusted price, like TimeSeriesDaily.Summary.
+func (t TimeSeriesMonthlyAdjusted) Summary(field PriceField) SeriesStats {
+	return summarize(unadjusted(t.TimeSeries), field)
+}
+
+// Summary computes the SeriesStats of the selected price, like TimeSeriesDaily.Summary.
+func (c CryptoSeriesResponse) Summary(field PriceField) SeriesStats {
+	bars := make([]OHLCV, len(c.TimeSeries))
+	for i, v := range c.TimeSeries {
+		bars[i] = OHLCV{Timestamp: v.Timestamp, Open: v.Open, High: v.High, Low: v.Low, Close: v.Close}
+	}
+	return summarize(bars, field)
+}
+
+// TotalReturn computes the return from the first bar on or after from to the last bar on or
+// before to, using adjusted closes so that dividends and splits are included. It returns
+// ErrEmptySeries if no bar falls in the window, and an error if the first adjusted close in it
+// is zero, as no return is defined from a zero price.
+func (t TimeSeriesDailyAdjusted) TotalReturn(from, to time.Time) (float64, error) {
+	first, last := -1, -1
+	for i, v := range t.TimeSeries {
+		if v.Timestamp.Before(from) || v.Timestamp.After(to) {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+
+	if first < 0 {
+		return 0, fmt.Errorf("%w: no bars between %s and %s", ErrEmptySeries, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	start := t.TimeSeries[first]
+	if start.AdjustedClose == 0 {
+		return 0, fmt.Errorf("zero adjusted close on %s", start.Timestamp.Format("2006-01-02"))
+	}
+	return t.TimeSeries[last].AdjustedClose/start.AdjustedClose - 1, nil
+}
+
+// CumulativeDividends sums the dividends of the bars between from and to, inclusive.
+func (t TimeSeriesDailyAdjusted) CumulativeDividends(from, to time.Time) float64 {
+	total := 0.0
+	for _, v := range t.TimeSeries {
+		if v.Timestamp.Before(from) || v.Timestamp.After(to) {
+			continue
+		}
+		total += v.Dividend
+	}
+	return total
+}
@@ -0,0 +1,401 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// closeTo reports whether got is within 1e-9 of want.
+func closeTo(got, want float64) bool {
+	return math.Abs(got-want) < 1e-9
+}
+
+func TestRollingCorrelation(t *testing.T) {
+	a := []float64{0.01, -0.02, 0.015, 0.03, -0.01, 0.005}
+	negated := make([]float64, len(a))
+	for i, v := range a {
+		negated[i] = -v
+	}
+
+	same, err := RollingCorrelation(a, a, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opposite, err := RollingCorrelation(a, negated, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(same) != 4 || len(opposite) != 4 {
+		t.Fatalf("got %d and %d values, want one per full window, 4", len(same), len(opposite))
+	}
+	for i := range same {
+		if !closeTo(same[i], 1) || !closeTo(opposite[i], -1) {
+			t.Errorf("window %d: correlations %v and %v, want 1 and -1", i, same[i], opposite[i])
+		}
+	}
+
+	// A constant window has no defined correlation
+	flat, err := RollingCorrelation([]float64{1, 1, 1}, []float64{1, 2, 3}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(flat[0]) {
+		t.Errorf("constant window: correlation %v, want NaN", flat[0])
+	}
+}
+
+func TestRollingCorrelationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   []float64
+		window int
+	}{
+		{"mismatched lengths", []float64{1, 2, 3}, []float64{1, 2}, 2},
+		{"window longer than input", []float64{1, 2, 3}, []float64{1, 2, 3}, 4},
+		{"window too small", []float64{1, 2, 3}, []float64{1, 2, 3}, 1},
+	}
+	for _, tt := range tests {
+		if _, err := RollingCorrelation(tt.a, tt.b, tt.window); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestBeta(t *testing.T) {
+	market := []float64{0.01, -0.02, 0.015, 0.03}
+	double := []float64{0.02, -0.04, 0.03, 0.06}
+
+	beta, err := Beta(double, market)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(beta, 2) {
+		t.Errorf("beta = %v, want 2", beta)
+	}
+	if beta, err := Beta(market, market); err != nil || !closeTo(beta, 1) {
+		t.Errorf("beta of the market = %v, %v, want 1", beta, err)
+	}
+
+	if _, err := Beta(double, market[:3]); err == nil {
+		t.Error("mismatched lengths: expected an error")
+	}
+	if _, err := Beta([]float64{0.01}, []float64{0.01}); err == nil {
+		t.Error("single value: expected an error")
+	}
+	if _, err := Beta(double, []float64{0.01, 0.01, 0.01, 0.01}); err == nil {
+		t.Error("constant market: expected an error")
+	}
+}
+
+// line builds single-output indicator values named name, one per day from March 1, 2024,
+// skipping the days listed in skip.
+func line(name string, values []float64, skip ...int) []IndicatorValue {
+	skipped := make(map[int]bool, len(skip))
+	for _, day := range skip {
+		skipped[day] = true
+	}
+	var points []IndicatorValue
+	for i, value := range values {
+		if skipped[i] {
+			continue
+		}
+		points = append(points, IndicatorValue{Timestamp: date(2024, 3, 1+i), Values: map[string]float64{name: value}})
+	}
+	return points
+}
+
+func TestCrossovers(t *testing.T) {
+	tests := []struct {
+		name       string
+		fast, slow []IndicatorValue
+		want       []Signal
+	}{
+		{
+			"up-cross",
+			line("EMA", []float64{1, 2, 4}), line("SMA", []float64{3, 3, 3}),
+			[]Signal{{date(2024, 3, 3), SignalBullish}},
+		},
+		{
+			"down-cross",
+			line("EMA", []float64{4, 3, 1}), line("SMA", []float64{2, 2, 2}),
+			[]Signal{{date(2024, 3, 3), SignalBearish}},
+		},
+		{
+			"cross through equality",
+			line("EMA", []float64{1, 2, 3}), line("SMA", []float64{2, 2, 2}),
+			[]Signal{{date(2024, 3, 3), SignalBullish}},
+		},
+		{
+			"touch without crossing",
+			line("EMA", []float64{1, 2, 1}), line("SMA", []float64{2, 2, 2}),
+			nil,
+		},
+		{
+			"misaligned timestamps",
+			// The slow line lacks March 2 and the fast line March 4; only March 1, 3 and 5 are compared
+			line("EMA", []float64{1, 9, 4, 9, 1}, 3), line("SMA", []float64{3, 0, 3, 0, 3}, 1),
+			[]Signal{{date(2024, 3, 3), SignalBullish}, {date(2024, 3, 5), SignalBearish}},
+		},
+		{
+			"multi-output values skipped",
+			[]IndicatorValue{
+				{Timestamp: date(2024, 3, 1), Values: map[string]float64{"EMA": 1}},
+				{Timestamp: date(2024, 3, 2), Values: map[string]float64{"MACD": 5, "MACD_Signal": 4}},
+			},
+			line("SMA", []float64{3, 3}),
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Crossovers(tt.fast, tt.slow)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Timestamp.Equal(tt.want[i].Timestamp) || got[i].Type != tt.want[i].Type {
+					t.Errorf("signal %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// closeBars returns one daily bar per price, from 2023-09-01, with every price of the bar set
+// to it.
+func closeBars(prices ...float64) []OHLCV {
+	bars := make([]OHLCV, len(prices))
+	for i, price := range prices {
+		bars[i] = OHLCV{Timestamp: date(2023, 9, 1+i), Open: price, High: price, Low: price, Close: price}
+	}
+	return bars
+}
+
+// checkAverage compares the values of a moving average, stored under key, with want, and
+// checks that they are aligned to the last len(want) bars.
+func checkAverage(t *testing.T, got []IndicatorValue, key string, bars []OHLCV, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d values, want %d", len(got), len(want))
+	}
+	offset := len(bars) - len(want)
+	for i, v := range got {
+		if !v.Timestamp.Equal(bars[offset+i].Timestamp) {
+			t.Errorf("value %d at %v, want %v", i, v.Timestamp, bars[offset+i].Timestamp)
+		}
+		if !closeTo(v.Values[key], want[i]) {
+			t.Errorf("%s %d = %v, want %v", key, i, v.Values[key], want[i])
+		}
+	}
+}
+
+func TestSMA(t *testing.T) {
+	bars := closeBars(2, 4, 6, 8, 12, 0)
+
+	sma, err := SMA(bars, 3, PriceClose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A zero price is averaged like any other
+	checkAverage(t, sma, "SMA", bars, []float64{4, 6, 26.0 / 3, 20.0 / 3})
+
+	// A period of one reproduces the prices, and one of the full length is a single average
+	one, err := SMA(bars, 1, PriceClose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAverage(t, one, "SMA", bars, []float64{2, 4, 6, 8, 12, 0})
+	full, err := SMA(bars, len(bars), PriceClose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAverage(t, full, "SMA", bars, []float64{32.0 / 6})
+}
+
+func TestEMA(t *testing.T) {
+	bars := closeBars(2, 4, 6, 8, 12, 0)
+
+	// With a period of 3 the smoothing factor is 2/(3+1) = 0.5, and the seed is the SMA of
+	// the first three prices, 4
+	ema, err := EMA(bars, 3, PriceClose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAverage(t, ema, "EMA", bars, []float64{4, 6, 9, 4.5})
+
+	// The selected field is used rather than the close
+	bars[3].High = 16
+	high, err := EMA(bars, 3, PriceHigh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAverage(t, high, "EMA", bars, []float64{4, 10, 11, 5.5})
+}
+
+func TestMovingAverageErrors(t *testing.T) {
+	bars := closeBars(1, 2, 3)
+	averages := map[string]func([]OHLCV, int, PriceField) ([]IndicatorValue, error){"SMA": SMA, "EMA": EMA}
+
+	for name, average := range averages {
+		t.Run(name, func(t *testing.T) {
+			if _, err := average(bars, 4, PriceClose); err == nil {
+				t.Error("period longer than the series: expected an error")
+			}
+			if _, err := average(nil, 1, PriceClose); err == nil {
+				t.Error("empty series: expected an error")
+			}
+			if _, err := average(bars, 0, PriceClose); err == nil {
+				t.Error("zero period: expected an error")
+			}
+			if _, err := average(bars, 2, "volume"); err == nil {
+				t.Error("invalid field: expected an error")
+			}
+		})
+	}
+}
+
+// checkReturns compares returns with want, where a NaN in want expects a NaN and an
+// infinity the same infinity.
+func checkReturns(t *testing.T, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d returns, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got[i]) {
+				t.Errorf("return %d = %v, want NaN", i, got[i])
+			}
+			continue
+		}
+		if got[i] != want[i] && !closeTo(got[i], want[i]) {
+			t.Errorf("return %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReturns(t *testing.T) {
+	daily := TimeSeriesDaily{TimeSeries: closeBars(100, 110, 99, 0, 50)}
+
+	// The move to zero is a total loss; the move from it has no defined return
+	checkReturns(t, daily.Returns(), []float64{0.1, -0.1, -1, math.NaN()})
+	checkReturns(t, daily.LogReturns(), []float64{math.Log(1.1), math.Log(0.9), math.Inf(-1), math.NaN()})
+
+	for _, n := range []int{0, 1} {
+		short := TimeSeriesDaily{TimeSeries: closeBars(100)[:n]}
+		if got := short.Returns(); got == nil || len(got) != 0 {
+			t.Errorf("%d bars: Returns = %v, want an empty slice", n, got)
+		}
+		if got := short.LogReturns(); got == nil || len(got) != 0 {
+			t.Errorf("%d bars: LogReturns = %v, want an empty slice", n, got)
+		}
+	}
+}
+
+func TestAdjustedReturns(t *testing.T) {
+	// The close halves on a 2:1 split while the adjusted close rises 10%
+	adjusted := TimeSeriesDailyAdjusted{TimeSeries: []AdjustedOHLCV{
+		{OHLCV: OHLCV{Timestamp: date(2023, 9, 1), Close: 200}, AdjustedClose: 100},
+		{OHLCV: OHLCV{Timestamp: date(2023, 9, 5), Close: 110}, AdjustedClose: 110},
+	}}
+	checkReturns(t, adjusted.AdjustedReturns(), []float64{0.1})
+	checkReturns(t, adjusted.AdjustedLogReturns(), []float64{math.Log(1.1)})
+}
+
+func TestTotalReturn(t *testing.T) {
+	adjusted := TimeSeriesDailyAdjusted{TimeSeries: []AdjustedOHLCV{
+		{OHLCV: OHLCV{Timestamp: date(2023, 9, 1)}, AdjustedClose: 0},
+		{OHLCV: OHLCV{Timestamp: date(2023, 9, 5)}, AdjustedClose: 100},
+		{OHLCV: OHLCV{Timestamp: date(2023, 9, 6)}, AdjustedClose: 104, Dividend: 1.5},
+		{OHLCV: OHLCV{Timestamp: date(2023, 9, 7)}, AdjustedClose: 98},
+		{OHLCV: OHLCV{Timestamp: date(2023, 9, 8)}, AdjustedClose: 120, Dividend: 0.5},
+	}}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     float64
+	}{
+		{"exact bounds", date(2023, 9, 5), date(2023, 9, 8), 0.2},
+		{"bounds between bars", date(2023, 9, 2), date(2023, 9, 7), -0.02},
+		{"bound past the series", date(2023, 9, 6), date(2023, 12, 31), 120.0/104 - 1},
+		{"single bar", date(2023, 9, 6), date(2023, 9, 6), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adjusted.TotalReturn(tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !closeTo(got, tt.want) {
+				t.Errorf("TotalReturn = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("zero first price", func(t *testing.T) {
+		if got, err := adjusted.TotalReturn(date(2023, 9, 1), date(2023, 9, 8)); err == nil {
+			t.Errorf("TotalReturn = %v, want an error", got)
+		}
+	})
+
+	t.Run("empty window", func(t *testing.T) {
+		if _, err := adjusted.TotalReturn(date(2023, 9, 2), date(2023, 9, 4)); !errors.Is(err, ErrEmptySeries) {
+			t.Errorf("err = %v, want ErrEmptySeries", err)
+		}
+		if _, err := (TimeSeriesDailyAdjusted{}).TotalReturn(date(2023, 9, 1), date(2023, 9, 8)); !errors.Is(err, ErrEmptySeries) {
+			t.Errorf("empty series: err = %v, want ErrEmptySeries", err)
+		}
+	})
+
+	if got := adjusted.CumulativeDividends(date(2023, 9, 6), date(2023, 9, 8)); !closeTo(got, 2) {
+		t.Errorf("CumulativeDividends = %v, want 2", got)
+	}
+	if got := adjusted.CumulativeDividends(date(2023, 9, 7), date(2023, 9, 7)); got != 0 {
+		t.Errorf("CumulativeDividends without dividends = %v, want 0", got)
+	}
+}
+
+func TestFullyAdjusted(t *testing.T) {
+	adjusted := TimeSeriesDailyAdjusted{
+		MetaData: TimeSeriesMetaData{Symbol: "IBM"},
+		TimeSeries: []AdjustedOHLCV{
+			// Before a 2:1 split, every price is halved
+			{OHLCV: OHLCV{Timestamp: date(2023, 9, 1), Open: 200, High: 210, Low: 190, Close: 204, Volume: 1000}, AdjustedClose: 102},
+			// After it, the adjusted close equals the close and the bar is unchanged
+			{OHLCV: OHLCV{Timestamp: date(2023, 9, 5), Open: 103, High: 106, Low: 101, Close: 105, Volume: 2000}, AdjustedClose: 105},
+			// A zero close cannot be scaled
+			{OHLCV: OHLCV{Timestamp: date(2023, 9, 6), Open: 1, High: 2, Low: 0, Close: 0, Volume: 5}, AdjustedClose: 3},
+		},
+	}
+
+	daily := adjusted.FullyAdjusted()
+	want := []OHLCV{
+		{Timestamp: date(2023, 9, 1), Open: 100, High: 105, Low: 95, Close: 102, Volume: 1000},
+		{Timestamp: date(2023, 9, 5), Open: 103, High: 106, Low: 101, Close: 105, Volume: 2000},
+		{Timestamp: date(2023, 9, 6), Open: 1, High: 2, Low: 0, Close: 0, Volume: 5},
+	}
+	if len(daily.TimeSeries) != len(want) {
+		t.Fatalf("got %d bars, want %d", len(daily.TimeSeries), len(want))
+	}
+	for i, w := range want {
+		got := daily.TimeSeries[i]
+		if !got.Timestamp.Equal(w.Timestamp) || got.Volume != w.Volume ||
+			!closeTo(got.Open, w.Open) || !closeTo(got.High, w.High) || !closeTo(got.Low, w.Low) || !closeTo(got.Close, w.Close) {
+			t.Errorf("bar %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if daily.MetaData.Symbol != "IBM" {
+		t.Errorf("metadata not kept: %+v", daily.MetaData)
+	}
+	// The adjusted series itself is left alone
+	if adjusted.TimeSeries[0].Open != 200 {
+		t.Errorf("FullyAdjusted modified its receiver: %+v", adjusted.TimeSeries[0])
+	}
+
+	if got := (TimeSeriesDailyAdjusted{}).FullyAdjusted(); len(got.TimeSeries) != 0 {
+		t.Errorf("got %d bars from an empty series", len(got.TimeSeries))
+	}
+}
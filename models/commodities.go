@@ -0,0 +1,119 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage commodity data.
+//
+// This file contains types and functions representing the interactions and responses
+// for commodity prices provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DataPoint represents a single dated observation of a commodity or economic series.
+type DataPoint struct {
+	Date  time.Time
+	Value float64
+}
+
+// CommoditySeries represents the response for the commodity endpoints such as WTI.
+type CommoditySeries struct {
+	Name     string
+	Interval string
+	Unit     string
+	Data     []DataPoint
+}
+
+// rawDataSeries is the {"name", "interval", "unit", "data": [{"date", "value"}]} shape shared
+// by the commodity and economic indicator endpoints.
+type rawDataSeries struct {
+	Name     string `json:"name"`
+	Interval string `json:"interval"`
+	Unit     string `json:"unit"`
+	Data     []struct {
+		Date  string `json:"date"`
+		Value string `json:"value"`
+	} `json:"data"`
+}
+
+// UnmarshalJSON is a custom unmarshaler for the CommoditySeries struct.
+func (s *CommoditySeries) UnmarshalJSON(data []byte) error {
+	var raw rawDataSeries
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	points, err := parseDataPoints(raw)
+	if err != nil {
+		return err
+	}
+
+	s.Name = raw.Name
+	s.Interval = raw.Interval
+	s.Unit = raw.Unit
+	s.Data = points
+	return nil
+}
+
+// parseDataPoints converts the raw observations into DataPoints sorted ascending by date.
+// Missing observations, reported with the value ".", are skipped.
+func parseDataPoints(raw rawDataSeries) ([]DataPoint, error) {
+	points := make([]DataPoint, 0, len(raw.Data))
+	for _, d := range raw.Data {
+		if d.Value == "." {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'date': %v", err)
+		}
+
+		value, err := strconv.ParseFloat(d.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'value' for %s: %v", d.Date, err)
+		}
+
+		points = append(points, DataPoint{Date: date, Value: value})
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Date.Before(points[j].Date)
+	})
+
+	return points, nil
+}
+
+// writeDataPoints renders the metadata and observations shared by the dated series.
+func writeDataPoints(name, interval, unit string, points []DataPoint) string {
+	var sb strings.Builder
+
+	// First, print metadata
+	sb.WriteString(name + "\n")
+	sb.WriteString(fmt.Sprintf("Interval: %s\n", interval))
+	sb.WriteString(fmt.Sprintf("Unit: %s\n", unit))
+	sb.WriteString("\n")
+
+	prec := precision(4)
+	rows := make([][]string, 0, len(points))
+	for _, p := range points {
+		rows = append(rows, []string{p.Date.Format("2006-01-02"), fmt.Sprintf("%.*f", prec, p.Value)})
+	}
+	writeTable(&sb, []string{"Date", "Value"}, rows)
+
+	return sb.String()
+}
+
+// String representation of the CommoditySeries for custom printing.
+func (s CommoditySeries) String() string {
+	return writeDataPoints(s.Name, s.Interval, s.Unit, s.Data)
+}
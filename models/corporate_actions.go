@@ -0,0 +1,124 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage corporate action data.
+//
+// This file contains types and functions representing the interactions and responses 
+// for the SPLITS and DIVIDENDS endpoints provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Split represents a single stock split of the SPLITS response.
+type Split struct {
+	EffectiveDate time.Time
+	SplitFactor   float64 // new shares per old share, e.g. 4 for a 4-for-1 split
+}
+
+// Dividend represents a single cash dividend of the DIVIDENDS response. The dates other
+// than ExDividendDate are zero when the API reports them as "None".
+type Dividend struct {
+	ExDividendDate  time.Time
+	DeclarationDate time.Time
+	RecordDate      time.Time
+	PaymentDate     time.Time
+	Amount          float64
+}
+
+// UnmarshalSplitsJSON parses the "data" array of the SPLITS response into splits, oldest first.
+func UnmarshalSplitsJSON(splits *[]Split, data []byte) error {
+	var raw struct {
+		Data []map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed := make([]Split, 0, len(raw.Data))
+	for _, rawSplit := range raw.Data {
+		effectiveDate, err := time.Parse("2006-01-02", rawSplit["effective_date"])
+		if err != nil {
+			return fmt.Errorf("error parsing 'effective_date': %v", err)
+		}
+
+		splitFactor, err := strconv.ParseFloat(rawSplit["split_factor"], 64)
+		if err != nil {
+			return fmt.Errorf("error parsing 'split_factor' for %s: %v", rawSplit["effective_date"], err)
+		}
+
+		parsed = append(parsed, Split{EffectiveDate: effectiveDate, SplitFactor: splitFactor})
+	}
+
+	sort.SliceStable(parsed, func(a, b int) bool {
+		return parsed[a].EffectiveDate.Before(parsed[b].EffectiveDate)
+	})
+
+	*splits = parsed
+	return nil
+}
+
+// UnmarshalDividendsJSON parses the "data" array of the DIVIDENDS response into dividends,
+// oldest ex-dividend date first.
+func UnmarshalDividendsJSON(dividends *[]Dividend, data []byte) error {
+	var raw struct {
+		Data []map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed := make([]Dividend, 0, len(raw.Data))
+	for _, rawDividend := range raw.Data {
+		var d Dividend
+
+		exDividendDate, err := time.Parse("2006-01-02", rawDividend["ex_dividend_date"])
+		if err != nil {
+			return fmt.Errorf("error parsing 'ex_dividend_date': %v", err)
+		}
+		d.ExDividendDate = exDividendDate
+
+		optionalDates := []struct {
+			name string
+			dst  *time.Time
+		}{
+			{"declaration_date", &d.DeclarationDate},
+			{"record_date", &d.RecordDate},
+			{"payment_date", &d.PaymentDate},
+		}
+		for _, field := range optionalDates {
+			value := rawDividend[field.name]
+			if value == "" || value == "None" {
+				continue
+			}
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return fmt.Errorf("error parsing '%s' for %s: %v", field.name, rawDividend["ex_dividend_date"], err)
+			}
+			*field.dst = date
+		}
+
+		amount, err := strconv.ParseFloat(rawDividend["amount"], 64)
+		if err != nil {
+			return fmt.Errorf("error parsing 'amount' for %s: %v", rawDividend["ex_dividend_date"], err)
+		}
+		d.Amount = amount
+
+		parsed = append(parsed, d)
+	}
+
+	sort.SliceStable(parsed, func(a, b int) bool {
+		return parsed[a].ExDividendDate.Before(parsed[b].ExDividendDate)
+	})
+
+	*dividends = parsed
+	return nil
+}
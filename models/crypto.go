@@ -14,18 +14,72 @@ import (
 	"time"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"strconv"
 )
 
 type CryptoParams struct {
-	Function   string
-	Symbol     string
-	Interval   string
-	Market     string
-	DataType   string
-	OutputSize string
+	Function    string
+	Symbol      string
+	Interval    string
+	Market      string
+	DataType    DataType
+	OutputSize  OutputSize
+	Entitlement string // "realtime" or "delayed" for premium plans; empty leaves the API default
+}
+
+// NormalizeMarket trims surrounding whitespace from market and upper-cases it, the form of the
+// currency codes in the keys of crypto responses, e.g. " usd " becomes "USD".
+func NormalizeMarket(market string) string {
+	return strings.ToUpper(strings.TrimSpace(market))
+}
+
+// ValidateMarket rejects markets that are empty or cannot be a currency code: the physical
+// currencies are three-letter codes such as USD, and the digital quote assets, such as BTC or
+// USDT, are no longer than five letters. Whether the API quotes a symbol in a well-formed market
+// is left to the API, whose list of currencies changes over time. market is normalized by
+// NormalizeMarket first.
+func ValidateMarket(market string) error {
+	market = NormalizeMarket(market)
+	if market == "" {
+		return fmt.Errorf("empty market")
+	}
+	if !isCurrencyCode(market) {
+		return fmt.Errorf("invalid market %q: must be a currency code such as USD, EUR or BTC", market)
+	}
+	return nil
+}
+
+// isCurrencyCode reports whether code has the shape of a currency code: three to five
+// upper-case letters.
+func isCurrencyCode(code string) bool {
+	if len(code) < 3 || len(code) > 5 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the parameters the API would otherwise answer with an opaque error message
+// or empty bars: the market must be a currency code, in any case, and the interval and
+// output size, if set, ones the API knows.
+func (p CryptoParams) Validate() error {
+	if err := ValidateMarket(p.Market); err != nil {
+		return err
+	}
+	if interval := Interval(p.Interval); interval != "" && !interval.IsValid() {
+		return invalidIntervalError(interval)
+	}
+	if p.OutputSize != "" && !p.OutputSize.IsValid() {
+		return fmt.Errorf("invalid output size %q: must be compact or full", p.OutputSize)
+	}
+	return nil
 }
 
 type CurrencyExchangeParams struct {
@@ -37,7 +91,7 @@ type CryptoExchangeRateParams struct {
 	Function      string
 	FromCurrency  string
 	ToCurrency    string
-	DataType      string
+	DataType      DataType
 }
 
 type CurrencyExchangeRateResponse struct {
@@ -69,6 +123,8 @@ type CryptoMetaData struct {
 	MarketCode          string
 	MarketName          string
 	LastRefreshed       string
+	Interval            string // intraday only
+	OutputSize          string // intraday only
 	TimeZone            string
 }
 
@@ -79,10 +135,59 @@ type CryptoTimeSeriesData struct {
 	Low       float64
 	Close     float64
 	Volume    float64
-	MarketCap float64
+	MarketCap float64 // zero unless the response quotes it in the requested market
+}
+
+// Reset clears the CryptoSeriesResponse so that it can be reused.
+func (c *CryptoSeriesResponse) Reset() {
+	*c = CryptoSeriesResponse{}
+}
+
+// Clone returns a copy of the CryptoSeriesResponse that shares no bars with c.
+func (c CryptoSeriesResponse) Clone() CryptoSeriesResponse {
+	clone := c
+	clone.TimeSeries = append([]CryptoTimeSeriesData(nil), c.TimeSeries...)
+	return clone
+}
+
+// SortDescending returns a copy of the CryptoSeriesResponse with the bars newest first, like
+// TimeSeriesIntraday.SortDescending.
+func (c CryptoSeriesResponse) SortDescending() CryptoSeriesResponse {
+	sorted := c
+	sorted.TimeSeries = sortDescending(c.TimeSeries, func(bar CryptoTimeSeriesData) time.Time {
+		return bar.Timestamp
+	})
+	return sorted
+}
+
+// Filter returns a copy of the CryptoSeriesResponse holding only the bars for which keep returns true.
+func (c CryptoSeriesResponse) Filter(keep func(CryptoTimeSeriesData) bool) CryptoSeriesResponse {
+	filtered := c
+	filtered.TimeSeries = filterBars(c.TimeSeries, keep)
+	return filtered
+}
+
+// DropZeroBars returns a copy of the CryptoSeriesResponse without the bars whose open, high,
+// low and close are all zero.
+func (c CryptoSeriesResponse) DropZeroBars() CryptoSeriesResponse {
+	return c.Filter(func(bar CryptoTimeSeriesData) bool {
+		return bar.Open != 0 || bar.High != 0 || bar.Low != 0 || bar.Close != 0
+	})
+}
+
+// At returns the bar stamped ts and whether there is one. For daily, weekly and monthly series,
+// which carry no interval in their metadata, the calendar date of ts is looked up instead.
+func (c CryptoSeriesResponse) At(ts time.Time) (CryptoTimeSeriesData, bool) {
+	if c.MetaData.Interval == "" {
+		ts = dateOf(ts)
+	}
+	return searchBar(c.TimeSeries, ts, func(bar CryptoTimeSeriesData) time.Time { return bar.Timestamp })
 }
 
 func UnmarshalCryptoJSON(c *CryptoSeriesResponse, data []byte) error {
+	// Start from scratch so that bars of a previous response do not accumulate
+	c.Reset()
+
 	var raw map[string]interface{}
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
@@ -94,37 +199,64 @@ func UnmarshalCryptoJSON(c *CryptoSeriesResponse, data []byte) error {
 		c.MetaData = extractCryptoMetaData(metaData)
 	}
 
+	var parseErrs ParseErrors
 	for tsKey, tsData := range raw {
 		if strings.HasPrefix(tsKey, "Time Series") {
 			c.IntervalLabel = tsKey
-			timeSeriesMap := tsData.(map[string]interface{})
+			timeSeriesMap, ok := tsData.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("expected map for %q", tsKey)
+			}
+			// Intraday bars are keyed by datetime, the others by date
+			layout := "2006-01-02"
+			if isCryptoIntradayLabel(tsKey) {
+				layout = "2006-01-02 15:04:05"
+			}
 			for date, values := range timeSeriesMap {
-				timestamp, err := time.Parse("2006-01-02", date)
+				timestamp, err := time.Parse(layout, date)
 				if err != nil {
-					return err
+					parseErrs = append(parseErrs, err)
+					continue
 				}
 
 				valuesMap, ok := values.(map[string]interface{})
 				if !ok {
-					return fmt.Errorf("expected map for timestamp data")
+					parseErrs = append(parseErrs, fmt.Errorf("expected map for timestamp data of %s", date))
+					continue
+				}
+
+				fields := extractCryptoFields(valuesMap, c.MetaData.MarketCode)
+				bar := CryptoTimeSeriesData{Timestamp: timestamp}
+				valid := true
+				for name, target := range map[string]*float64{
+					"open":       &bar.Open,
+					"high":       &bar.High,
+					"low":        &bar.Low,
+					"close":      &bar.Close,
+					"volume":     &bar.Volume,
+					"market cap": &bar.MarketCap,
+				} {
+					value, ok := fields[name]
+					if !ok {
+						// Not every response carries a market cap, nor one in every market
+						if name != "market cap" {
+							parseErrs = append(parseErrs, fmt.Errorf("missing %s in market %q for %s", name, c.MetaData.MarketCode, date))
+							valid = false
+						}
+						continue
+					}
+					parsed, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						parseErrs = append(parseErrs, fmt.Errorf("error parsing %s for %s: %v", name, date, err))
+						valid = false
+						continue
+					}
+					*target = parsed
 				}
 
-				open, _ := strconv.ParseFloat(valuesMap["1a. open (USD)"].(string), 64)
-				high, _ := strconv.ParseFloat(valuesMap["2a. high (USD)"].(string), 64)
-				low, _ := strconv.ParseFloat(valuesMap["3a. low (USD)"].(string), 64)
-				closeVal, _ := strconv.ParseFloat(valuesMap["4a. close (USD)"].(string), 64)
-				volume, _ := strconv.ParseFloat(valuesMap["5. volume"].(string), 64)
-				marketCap, _ := strconv.ParseFloat(valuesMap["6. market cap (USD)"].(string), 64)
-
-				c.TimeSeries = append(c.TimeSeries, CryptoTimeSeriesData{
-					Timestamp: timestamp,
-					Open:      open,
-					High:      high,
-					Low:       low,
-					Close:     closeVal,
-					Volume:    volume,
-					MarketCap: marketCap,
-				})
+				if valid {
+					c.TimeSeries = append(c.TimeSeries, bar)
+				}
 			}
 		}
 	}
@@ -134,35 +266,170 @@ func UnmarshalCryptoJSON(c *CryptoSeriesResponse, data []byte) error {
 		return c.TimeSeries[a].Timestamp.Before(c.TimeSeries[b].Timestamp)
 	})
 
-	return nil
+	// Bars with a malformed timestamp or field are skipped; the failures are reported
+	// together so a format change in the API does not pass silently.
+	return parseErrs.err()
+}
+
+// extractCryptoFields maps the values of a single bar by field name ("open", "market cap", ...).
+// Older responses carry every price twice, e.g. "1a. open (EUR)" and "1b. open (USD)", while
+// newer ones only have "1. open". Only values without a currency or quoted in market are kept,
+// so a field missing in market is missing from the result rather than filled in from another
+// currency. Without a market, USD is used if present, else the first currency in sorted order.
+func extractCryptoFields(valuesMap map[string]interface{}, market string) map[string]string {
+	byCurrency := make(map[string]map[string]string)
+
+	for key, rawValue := range valuesMap {
+		value, ok := rawValue.(string)
+		if !ok {
+			continue
+		}
+
+		// Strip the "1a. " style numbering
+		name := key
+		if idx := strings.Index(name, ". "); idx >= 0 {
+			name = name[idx+2:]
+		}
+
+		// Split off the " (USD)" style currency
+		currency := ""
+		if idx := strings.LastIndex(name, " ("); idx >= 0 && strings.HasSuffix(name, ")") {
+			currency = NormalizeMarket(name[idx+2 : len(name)-1])
+			name = name[:idx]
+		}
+
+		if byCurrency[currency] == nil {
+			byCurrency[currency] = make(map[string]string)
+		}
+		byCurrency[currency][name] = value
+	}
+
+	market = NormalizeMarket(market)
+	if market == "" {
+		market = defaultCryptoMarket(byCurrency)
+	}
+
+	fields := make(map[string]string)
+	for name, value := range byCurrency[""] {
+		fields[name] = value
+	}
+	for name, value := range byCurrency[market] {
+		fields[name] = value
+	}
+	return fields
+}
+
+// defaultCryptoMarket picks the currency of a bar whose market is unknown: USD if the bar is
+// quoted in it, else the first currency in sorted order, or "" if no value has a currency.
+func defaultCryptoMarket(byCurrency map[string]map[string]string) string {
+	if _, ok := byCurrency["USD"]; ok {
+		return "USD"
+	}
+	currencies := make([]string, 0, len(byCurrency))
+	for currency := range byCurrency {
+		if currency != "" {
+			currencies = append(currencies, currency)
+		}
+	}
+	if len(currencies) == 0 {
+		return ""
+	}
+	sort.Strings(currencies)
+	return currencies[0]
+}
+
+// isCryptoIntradayLabel reports whether the time series key is the one of CRYPTO_INTRADAY,
+// e.g. "Time Series Crypto (5min)", rather than "Time Series (Digital Currency Daily)".
+func isCryptoIntradayLabel(label string) bool {
+	return strings.HasPrefix(label, "Time Series Crypto")
 }
 
 func extractCryptoMetaData(rawData map[string]interface{}) CryptoMetaData {
 	var metaData CryptoMetaData
 
-	for key, value := range rawData {
+	for key, raw := range rawData {
+		// Values that are not strings are ignored rather than panicking on a malformed response
+		value, ok := raw.(string)
+		if !ok {
+			continue
+		}
 		switch key {
 		case "1. Information":
-			metaData.Information = value.(string)
+			metaData.Information = value
 		case "2. Digital Currency Code":
-			metaData.DigitalCurrencyCode = value.(string)
+			metaData.DigitalCurrencyCode = value
 		case "3. Digital Currency Name":
-			metaData.DigitalCurrencyName = value.(string)
+			metaData.DigitalCurrencyName = value
 		case "4. Market Code":
-			metaData.MarketCode = value.(string)
+			metaData.MarketCode = value
 		case "5. Market Name":
-			metaData.MarketName = value.(string)
+			metaData.MarketName = value
 		case "6. Last Refreshed":
-			metaData.LastRefreshed = value.(string)
-		case "7. Time Zone":
-			metaData.TimeZone = value.(string)
+			metaData.LastRefreshed = value
+		case "7. Time Zone", "9. Time Zone":
+			metaData.TimeZone = value
+		case "7. Interval":
+			metaData.Interval = value
+		case "8. Output Size":
+			metaData.OutputSize = value
 		}
 	}
 	return metaData
 }
 
+// MarshalJSON emits the CryptoSeriesResponse in the layout read by UnmarshalCryptoJSON,
+// with the simplified unsuffixed field names, so that it can be unmarshaled back.
+func (c CryptoSeriesResponse) MarshalJSON() ([]byte, error) {
+	metaData := map[string]string{
+		"1. Information":           c.MetaData.Information,
+		"2. Digital Currency Code": c.MetaData.DigitalCurrencyCode,
+		"3. Digital Currency Name": c.MetaData.DigitalCurrencyName,
+		"4. Market Code":           c.MetaData.MarketCode,
+		"5. Market Name":           c.MetaData.MarketName,
+		"6. Last Refreshed":        c.MetaData.LastRefreshed,
+	}
+
+	// Intraday responses number their metadata differently and have no market cap
+	intraday := isCryptoIntradayLabel(c.IntervalLabel)
+	layout := "2006-01-02"
+	if intraday {
+		metaData["7. Interval"] = c.MetaData.Interval
+		metaData["8. Output Size"] = c.MetaData.OutputSize
+		metaData["9. Time Zone"] = c.MetaData.TimeZone
+		layout = "2006-01-02 15:04:05"
+	} else {
+		metaData["7. Time Zone"] = c.MetaData.TimeZone
+	}
+
+	timeSeries := make(map[string]map[string]string, len(c.TimeSeries))
+	for _, v := range c.TimeSeries {
+		bar := map[string]string{
+			"1. open":   strconv.FormatFloat(v.Open, 'f', -1, 64),
+			"2. high":   strconv.FormatFloat(v.High, 'f', -1, 64),
+			"3. low":    strconv.FormatFloat(v.Low, 'f', -1, 64),
+			"4. close":  strconv.FormatFloat(v.Close, 'f', -1, 64),
+			"5. volume": strconv.FormatFloat(v.Volume, 'f', -1, 64),
+		}
+		if !intraday {
+			bar["6. market cap"] = strconv.FormatFloat(v.MarketCap, 'f', -1, 64)
+		}
+		timeSeries[v.Timestamp.Format(layout)] = bar
+	}
+
+	intervalLabel := c.IntervalLabel
+	if !strings.HasPrefix(intervalLabel, "Time Series") {
+		intervalLabel = "Time Series (Digital Currency)"
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"Meta Data":   metaData,
+		intervalLabel: timeSeries,
+	})
+}
+
 func (c CryptoSeriesResponse) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// Print metadata
 	sb.WriteString(c.MetaData.Information + "\n")
@@ -172,31 +439,47 @@ func (c CryptoSeriesResponse) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", c.MetaData.TimeZone))
 	sb.WriteString("\n")
 
+	// Column widths follow the data so market caps in the trillions stay aligned
 	headers := []string{"Time", "Open", "High", "Low", "Close", "Volume", "MarketCap"}
-
-	// Print headers
-	for _, header := range headers {
-		if header == "Time" {
-			sb.WriteString(fmt.Sprintf("%-25s", header)) // Increased space for the Time column
-		} else {
-			sb.WriteString(fmt.Sprintf("%-20s", header))
-		}
-	}
-	sb.WriteString("\n")
-	sb.WriteString(strings.Repeat("=", 25 + 20*(len(headers)-1))) // Adjusting the "=" line length
-	sb.WriteString("\n")
-
-	// Loop through the TimeSeries slice
+	rows := make([][]string, 0, len(c.TimeSeries))
 	for _, v := range c.TimeSeries {
-		timeStr := v.Timestamp.Format("2006-01-02 15:04:05")
-		sb.WriteString(fmt.Sprintf("%-25s%-20.2f%-20.2f%-20.2f%-20.2f%-20.2f%-20.2f", timeStr, v.Open, v.High, v.Low, v.Close, v.Volume, v.MarketCap))
-		sb.WriteString("\n")
+		rows = append(rows, []string{
+			v.Timestamp.Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%.*f", prec, v.Open),
+			fmt.Sprintf("%.*f", prec, v.High),
+			fmt.Sprintf("%.*f", prec, v.Low),
+			fmt.Sprintf("%.*f", prec, v.Close),
+			fmt.Sprintf("%.*f", prec, v.Volume),
+			fmt.Sprintf("%.*f", prec, v.MarketCap),
+		})
 	}
+	writeTable(&sb, headers, rows)
 
 	return sb.String()
 }
 
 
+// Rate returns ExchangeRate as a float.
+func (e ExchangeRateInfo) Rate() (float64, error) {
+	return strconv.ParseFloat(e.ExchangeRate, 64)
+}
+
+// Bid returns BidPrice as a float. Some pairs carry "-" instead of a price, which is an error.
+func (e ExchangeRateInfo) Bid() (float64, error) {
+	return strconv.ParseFloat(e.BidPrice, 64)
+}
+
+// Ask returns AskPrice as a float. Some pairs carry "-" instead of a price, which is an error.
+func (e ExchangeRateInfo) Ask() (float64, error) {
+	return strconv.ParseFloat(e.AskPrice, 64)
+}
+
+// LastRefreshedTime parses LastRefreshed in the "2006-01-02 15:04:05" layout of the API. The
+// result is in UTC, the zone the API reports in TimeZone.
+func (e ExchangeRateInfo) LastRefreshedTime() (time.Time, error) {
+	return time.Parse("2006-01-02 15:04:05", e.LastRefreshed)
+}
+
 // String function to nicely format the response for the Currency Exchange Rate API
 func (r CurrencyExchangeRateResponse) String() string {
 	return fmt.Sprintf(
@@ -210,3 +493,123 @@ func (r CurrencyExchangeRateResponse) String() string {
 		r.ExchangeRateInfo.AskPrice,
 	)
 }
+
+// CryptoRating represents the response for the CRYPTO_RATING endpoint: the Fundamental Crypto
+// Asset Score (FCAS) of a digital currency and its component scores.
+type CryptoRating struct {
+	Symbol              string
+	Name                string
+	FCASRating          string // e.g. "Superb" or "Attractive"
+	FCASScore           int
+	DeveloperScore      int
+	MarketMaturityScore int
+	UtilityScore        int
+	LastRefreshed       time.Time
+	TimeZone            string
+}
+
+// UnmarshalJSON is a custom unmarshaler for the CryptoRating struct.
+func (r *CryptoRating) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Rating map[string]string `json:"Crypto Rating (FCAS)"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Symbol = raw.Rating["1. symbol"]
+	r.Name = raw.Rating["2. name"]
+	r.FCASRating = raw.Rating["3. fcas rating"]
+	r.TimeZone = raw.Rating["9. timezone"]
+
+	scores := []struct {
+		key string
+		dst *int
+	}{
+		{"4. fcas score", &r.FCASScore},
+		{"5. developer score", &r.DeveloperScore},
+		{"6. market maturity score", &r.MarketMaturityScore},
+		{"7. utility score", &r.UtilityScore},
+	}
+	for _, score := range scores {
+		value, ok := raw.Rating[score.key]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("error parsing '%s' for %s: %v", score.key, r.Symbol, err)
+		}
+		*score.dst = parsed
+	}
+
+	if value, ok := raw.Rating["8. last refreshed"]; ok {
+		lastRefreshed, err := time.Parse("2006-01-02 15:04:05", value)
+		if err != nil {
+			return fmt.Errorf("error parsing '8. last refreshed' for %s: %v", r.Symbol, err)
+		}
+		r.LastRefreshed = lastRefreshed
+	}
+
+	return nil
+}
+
+// String representation of the CryptoRating for custom printing.
+func (r CryptoRating) String() string {
+	return fmt.Sprintf(
+		"Digital Currency: %s (%s)\nFCAS Rating: %s\nFCAS Score: %d\nDeveloper Score: %d\nMarket Maturity Score: %d\nUtility Score: %d\nLast Refreshed: %s\nTime Zone: %s",
+		r.Name, r.Symbol,
+		r.FCASRating,
+		r.FCASScore,
+		r.DeveloperScore,
+		r.MarketMaturityScore,
+		r.UtilityScore,
+		r.LastRefreshed.Format("2006-01-02 15:04:05"),
+		r.TimeZone,
+	)
+}
+
+// Records returns the CryptoSeriesResponse as CSV records: a header row followed by one row per bar.
+func (c CryptoSeriesResponse) Records() [][]string {
+	records := make([][]string, 0, len(c.TimeSeries)+1)
+	records = append(records, []string{"timestamp", "open", "high", "low", "close", "volume", "market_cap"})
+	for _, v := range c.TimeSeries {
+		records = append(records, []string{
+			v.Timestamp.Format(time.RFC3339),
+			strconv.FormatFloat(v.Open, 'f', -1, 64),
+			strconv.FormatFloat(v.High, 'f', -1, 64),
+			strconv.FormatFloat(v.Low, 'f', -1, 64),
+			strconv.FormatFloat(v.Close, 'f', -1, 64),
+			strconv.FormatFloat(v.Volume, 'f', -1, 64),
+			strconv.FormatFloat(v.MarketCap, 'f', -1, 64),
+		})
+	}
+	return records
+}
+
+// WriteCSV writes the CryptoSeriesResponse as CSV with a header row.
+func (c CryptoSeriesResponse) WriteCSV(w io.Writer) error {
+	return writeRecords(w, c.Records())
+}
+
+// Columns returns the TimeSeries as parallel slices, one per field.
+func (c CryptoSeriesResponse) Columns() (timestamps []time.Time, open, high, low, close, volume, marketCap []float64) {
+	timestamps = make([]time.Time, len(c.TimeSeries))
+	open = make([]float64, len(c.TimeSeries))
+	high = make([]float64, len(c.TimeSeries))
+	low = make([]float64, len(c.TimeSeries))
+	close = make([]float64, len(c.TimeSeries))
+	volume = make([]float64, len(c.TimeSeries))
+	marketCap = make([]float64, len(c.TimeSeries))
+
+	for i, v := range c.TimeSeries {
+		timestamps[i] = v.Timestamp
+		open[i] = v.Open
+		high[i] = v.High
+		low[i] = v.Low
+		close[i] = v.Close
+		volume[i] = v.Volume
+		marketCap[i] = v.MarketCap
+	}
+	return
+}
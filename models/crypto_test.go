@@ -0,0 +1,161 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// cryptoBody builds a daily crypto response with one bar, priced in each of the given
+// currencies, for the market named in the metadata.
+func cryptoBody(market string, currencies ...string) []byte {
+	body := `{"Meta Data": {"4. Market Code": "` + market + `"}, "Time Series (Digital Currency Daily)": {"2023-09-10": {`
+	for i, currency := range currencies {
+		price := []string{"10", "20", "30"}[i]
+		body += `"1. open (` + currency + `)": "` + price + `", "2. high (` + currency + `)": "` + price + `", "3. low (` + currency + `)": "` + price + `", "4. close (` + currency + `)": "` + price + `", `
+	}
+	return []byte(body + `"5. volume": "7"}}}`)
+}
+
+func TestUnmarshalCryptoJSONMarkets(t *testing.T) {
+	tests := []struct {
+		name       string
+		market     string
+		currencies []string
+		wantClose  float64
+		wantErr    bool
+	}{
+		{"requested market", "EUR", []string{"EUR", "USD"}, 10, false},
+		{"requested market second", "USD", []string{"EUR", "USD"}, 20, false},
+		{"lowercase market", "eur", []string{"USD", "EUR"}, 20, false},
+		{"missing market", "GBP", []string{"EUR", "USD"}, 0, true},
+		{"no market prefers USD", "", []string{"EUR", "USD", "GBP"}, 20, false},
+		{"no market sorted", "", []string{"JPY", "EUR"}, 20, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Decoding into a map randomizes the column order, so repeat to catch dependence on it
+			for run := 0; run < 20; run++ {
+				var crypto CryptoSeriesResponse
+				err := UnmarshalCryptoJSON(&crypto, cryptoBody(tt.market, tt.currencies...))
+				if tt.wantErr {
+					var parseErrs ParseErrors
+					if !errors.As(err, &parseErrs) || len(crypto.TimeSeries) != 0 {
+						t.Fatalf("err = %v with %d bars, want ParseErrors and no bars", err, len(crypto.TimeSeries))
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := crypto.TimeSeries[0].Close; got != tt.wantClose {
+					t.Fatalf("close = %v, want %v", got, tt.wantClose)
+				}
+			}
+		})
+	}
+}
+
+func TestUnmarshalCryptoJSONMarketCap(t *testing.T) {
+	var crypto CryptoSeriesResponse
+	if err := UnmarshalCryptoJSON(&crypto, readFixture(t, "crypto_daily.json")); err != nil {
+		t.Fatal(err)
+	}
+	// The fixture only quotes the market cap in USD, not in the requested EUR market
+	for _, bar := range crypto.TimeSeries {
+		if bar.MarketCap != 0 {
+			t.Errorf("market cap = %v, want 0", bar.MarketCap)
+		}
+	}
+}
+
+func TestUnmarshalCryptoJSONMalformedMetaData(t *testing.T) {
+	body := []byte(`{"Meta Data": {"2. Digital Currency Code": 42, "3. Digital Currency Name": null, "4. Market Code": "USD", "6. Last Refreshed": ["2023-09-10"]},
+		"Time Series (Digital Currency Daily)": {"2023-09-10": {"1. open (USD)": "1", "2. high (USD)": "1", "3. low (USD)": "1", "4. close (USD)": "1", "5. volume": "7"}}}`)
+
+	var crypto CryptoSeriesResponse
+	if err := UnmarshalCryptoJSON(&crypto, body); err != nil {
+		t.Fatal(err)
+	}
+	if crypto.MetaData.DigitalCurrencyCode != "" || crypto.MetaData.LastRefreshed != "" {
+		t.Errorf("non-string metadata decoded as %+v", crypto.MetaData)
+	}
+	if crypto.MetaData.MarketCode != "USD" || len(crypto.TimeSeries) != 1 {
+		t.Errorf("metadata %+v with %d bars, want market USD and 1 bar", crypto.MetaData, len(crypto.TimeSeries))
+	}
+}
+
+func TestNormalizeMarket(t *testing.T) {
+	for in, want := range map[string]string{" usd ": "USD", "eur": "EUR", "BTC": "BTC", "": ""} {
+		if got := NormalizeMarket(in); got != want {
+			t.Errorf("NormalizeMarket(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValidateMarket(t *testing.T) {
+	tests := []struct {
+		market  string
+		wantErr bool
+	}{
+		{"USD", false},
+		{"eur", false},
+		{" btc ", false},
+		{"SEK", false},
+		{"ISK", false},
+		{"usdt", false},
+		{"", true},
+		{"  ", true},
+		{"US", true},
+		{"DOLLARS", true},
+		{"US1", true},
+		{"U-S", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateMarket(tt.market); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateMarket(%q) = %v, want error %v", tt.market, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCryptoParamsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  CryptoParams
+		wantErr bool
+	}{
+		{"valid", CryptoParams{Symbol: "BTC", Market: "usd", OutputSize: OutputSizeFull}, false},
+		{"no output size", CryptoParams{Symbol: "BTC", Market: "EUR"}, false},
+		{"empty market", CryptoParams{Symbol: "BTC"}, true},
+		{"invalid market", CryptoParams{Symbol: "BTC", Market: "US DOLLAR"}, true},
+		{"invalid output size", CryptoParams{Symbol: "BTC", Market: "USD", OutputSize: "huge"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.params.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCryptoMarshalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"daily fixture", readFixture(t, "crypto_daily.json")},
+		{"intraday fixture", readFixture(t, "crypto_intraday.json")},
+		{"market without USD", cryptoBody("EUR", "EUR")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := roundTrip(t, tt.data, UnmarshalCryptoJSON)
+			if len(first.TimeSeries) == 0 {
+				t.Fatal("no bars decoded before marshaling")
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("round trip changed the value:\n got %+v\nwant %+v", second, first)
+			}
+		})
+	}
+}
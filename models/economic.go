@@ -0,0 +1,47 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage economic data.
+//
+// This file contains types and functions representing the interactions and responses
+// for the economic indicators provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+)
+
+// EconomicSeries represents the response for the economic indicator endpoints such as REAL_GDP.
+type EconomicSeries struct {
+	Name     string
+	Interval string
+	Unit     string
+	Data     []DataPoint
+}
+
+// UnmarshalJSON is a custom unmarshaler for the EconomicSeries struct.
+func (s *EconomicSeries) UnmarshalJSON(data []byte) error {
+	var raw rawDataSeries
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	points, err := parseDataPoints(raw)
+	if err != nil {
+		return err
+	}
+
+	s.Name = raw.Name
+	s.Interval = raw.Interval
+	s.Unit = raw.Unit
+	s.Data = points
+	return nil
+}
+
+// String representation of the EconomicSeries for custom printing.
+func (s EconomicSeries) String() string {
+	return writeDataPoints(s.Name, s.Interval, s.Unit, s.Data)
+}
@@ -0,0 +1,126 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage responses.
+//
+// This file contains the errors shared by the models and the client
+// when a response from the Alpha Vantage API carries no usable data.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrNoData is returned when the expected data section is missing from a response entirely,
+	// e.g. for an unknown symbol.
+	ErrNoData = errors.New("no data in response")
+
+	// ErrEmptySeries is returned when a series is present but holds no entries where at least one
+	// is required. It wraps ErrNoData, so that errors.Is(err, ErrNoData) holds for it as well.
+	ErrEmptySeries = fmt.Errorf("%w: empty series", ErrNoData)
+
+	// ErrPremiumEndpoint is returned when the API answers with its premium endpoint message
+	// instead of data because the API key's plan does not include the function.
+	ErrPremiumEndpoint = errors.New("premium endpoint")
+
+	// ErrNonJSONResponse is returned when the API, or a proxy in front of it, answers with an
+	// HTML page, e.g. during maintenance, instead of JSON or CSV data.
+	ErrNonJSONResponse = errors.New("non-JSON response")
+)
+
+// redacted replaces the API key in the query of APIError and RateLimitError.
+const redacted = "REDACTED"
+
+// APIError is returned when the API answers a request with an "Error Message" or "Information"
+// message instead of data. Function and Query describe the request, with the API key redacted,
+// so that the failing call can be told from logs. It wraps Err, ErrNoData or ErrPremiumEndpoint,
+// for errors.Is.
+type APIError struct {
+	Function string
+	Query    url.Values
+	Message  string
+	Err      error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v for %s: %s", e.Err, describeRequest(e.Function, e.Query), e.Message)
+}
+
+// Unwrap returns Err for errors.Is and errors.As.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when the API answers a request with its rate-limit note because
+// the API key exceeded its call frequency or daily quota. Function and Query describe the
+// request, with the API key redacted. It wraps ErrNoData, for errors.Is.
+type RateLimitError struct {
+	Function string
+	Query    url.Values
+	Message  string
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s: %s", describeRequest(e.Function, e.Query), e.Message)
+}
+
+// Unwrap returns ErrNoData for errors.Is.
+func (e *RateLimitError) Unwrap() error {
+	return ErrNoData
+}
+
+// RedactQuery returns a copy of query with the apikey parameter, if any, replaced by a
+// placeholder, so that it can be logged.
+func RedactQuery(query url.Values) url.Values {
+	clone := make(url.Values, len(query))
+	for key, v := range query {
+		clone[key] = append([]string(nil), v...)
+	}
+	if _, ok := clone["apikey"]; ok {
+		clone.Set("apikey", redacted)
+	}
+	return clone
+}
+
+// describeRequest formats the function and query of a failed request for an error message.
+func describeRequest(function string, query url.Values) string {
+	if function == "" {
+		function = "request"
+	}
+	if len(query) == 0 {
+		return function
+	}
+	return fmt.Sprintf("%s (%s)", function, query.Encode())
+}
+
+// ParseErrors collects the errors of the individual entries of a response that could not be
+// parsed and were skipped, while the other entries were kept.
+type ParseErrors []error
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d entries could not be parsed: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the individual errors for errors.Is and errors.As.
+func (e ParseErrors) Unwrap() []error {
+	return e
+}
+
+// err returns e as an error, or nil if it is empty.
+func (e ParseErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
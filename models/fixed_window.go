@@ -0,0 +1,141 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage analytics data.
+//
+// This file contains types and functions representing the interactions and responses
+// for the fixed window analytics provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AnalyticsParams represents the parameters for querying ANALYTICS_FIXED_WINDOW.
+type AnalyticsParams struct {
+	Symbols      []string // joined by commas
+	Range        []string // e.g. ["2023-07-01", "2023-08-31"] or ["full"]
+	Interval     string   // e.g. "DAILY"
+	OHLC         string   // e.g. "close"
+	Calculations []string // e.g. ["MEAN", "STDDEV", "CORRELATION"]
+}
+
+// AnalyticsMetaData represents the metadata of an analytics response.
+type AnalyticsMetaData struct {
+	Symbols  string `json:"symbols"`
+	MinDate  string `json:"min_dt"`
+	MaxDate  string `json:"max_dt"`
+	OHLC     string `json:"ohlc"`
+	Interval string `json:"interval"`
+}
+
+// AnalyticsMatrix represents a symmetric per-symbol matrix such as CORRELATION. The API sends
+// only the lower triangle: Values[i] holds the entries of Index[i] against Index[0..i].
+type AnalyticsMatrix struct {
+	Index  []string
+	Values [][]float64
+}
+
+// AnalyticsResult represents the response for the ANALYTICS_FIXED_WINDOW endpoint.
+type AnalyticsResult struct {
+	MetaData AnalyticsMetaData
+	// Statistics maps each per-symbol calculation, e.g. "MEAN", to its value for each symbol.
+	Statistics map[string]map[string]float64
+	// Matrices maps each pairwise calculation, e.g. "CORRELATION", to its matrix.
+	Matrices map[string]AnalyticsMatrix
+	// Other keeps the calculations whose shape is neither of the above, unparsed.
+	Other map[string]json.RawMessage
+}
+
+// UnmarshalJSON is a custom unmarshaler for the AnalyticsResult struct.
+func (a *AnalyticsResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		MetaData AnalyticsMetaData `json:"meta_data"`
+		Payload  struct {
+			ReturnsCalculations map[string]json.RawMessage `json:"RETURNS_CALCULATIONS"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	a.MetaData = raw.MetaData
+	a.Statistics = make(map[string]map[string]float64)
+	a.Matrices = make(map[string]AnalyticsMatrix)
+	a.Other = make(map[string]json.RawMessage)
+
+	for calculation, value := range raw.Payload.ReturnsCalculations {
+		var statistic map[string]float64
+		if err := json.Unmarshal(value, &statistic); err == nil {
+			a.Statistics[calculation] = statistic
+			continue
+		}
+
+		var matrix map[string]json.RawMessage
+		if err := json.Unmarshal(value, &matrix); err != nil {
+			return fmt.Errorf("error parsing '%s': %v", calculation, err)
+		}
+		if _, ok := matrix["index"]; !ok {
+			a.Other[calculation] = value
+			continue
+		}
+
+		var parsed AnalyticsMatrix
+		for key, rawValues := range matrix {
+			var err error
+			if key == "index" {
+				err = json.Unmarshal(rawValues, &parsed.Index)
+			} else {
+				// The values sit under the lowercased calculation, e.g. "correlation"
+				err = json.Unmarshal(rawValues, &parsed.Values)
+			}
+			if err != nil {
+				return fmt.Errorf("error parsing '%s': %v", calculation, err)
+			}
+		}
+		a.Matrices[calculation] = parsed
+	}
+
+	return nil
+}
+
+// Statistic returns the value of a per-symbol calculation such as "MEAN" for the symbol.
+func (a AnalyticsResult) Statistic(calculation, symbol string) (float64, bool) {
+	value, ok := a.Statistics[calculation][symbol]
+	return value, ok
+}
+
+// Correlation returns the correlation matrix, if CORRELATION was requested.
+func (a AnalyticsResult) Correlation() (AnalyticsMatrix, bool) {
+	matrix, ok := a.Matrices["CORRELATION"]
+	return matrix, ok
+}
+
+// At returns the entry for the pair of symbols, in either order.
+func (m AnalyticsMatrix) At(a, b string) (float64, bool) {
+	i, j := -1, -1
+	for k, symbol := range m.Index {
+		if symbol == a {
+			i = k
+		}
+		if symbol == b {
+			j = k
+		}
+	}
+	if i < 0 || j < 0 {
+		return 0, false
+	}
+
+	// Only the lower triangle is present
+	if j > i {
+		i, j = j, i
+	}
+	if i >= len(m.Values) || j >= len(m.Values[i]) {
+		return 0, false
+	}
+	return m.Values[i][j], true
+}
@@ -0,0 +1,420 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// readFixture returns the contents of the named file of the repository's testdata directory.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestUnmarshalFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		check   func(t *testing.T, data []byte)
+	}{
+		{"daily.json", func(t *testing.T, data []byte) {
+			var daily TimeSeriesDaily
+			if err := json.Unmarshal(data, &daily); err != nil {
+				t.Fatal(err)
+			}
+			if daily.MetaData.Symbol != "IBM" {
+				t.Errorf("symbol = %q, want IBM", daily.MetaData.Symbol)
+			}
+			want := []OHLCV{
+				{Timestamp: date(2023, 9, 6), Open: 147.91, High: 148.47, Low: 146.23, Close: 146.68, Volume: 3557436},
+				{Timestamp: date(2023, 9, 7), Open: 146.13, High: 148.11, Low: 145.92, Close: 147.52, Volume: 3854823},
+				{Timestamp: date(2023, 9, 8), Open: 147.49, High: 148.38, Low: 146.81, Close: 147.68, Volume: 2805923},
+			}
+			checkBars(t, daily.TimeSeries, want)
+		}},
+		{"intraday.json", func(t *testing.T, data []byte) {
+			var intraday TimeSeriesIntraday
+			if err := json.Unmarshal(data, &intraday); err != nil {
+				t.Fatal(err)
+			}
+			if intraday.MetaData.Interval != "5min" || intraday.MetaData.OutputSize != "Compact" || intraday.MetaData.TimeZone != "US/Eastern" {
+				t.Errorf("unexpected metadata %+v", intraday.MetaData)
+			}
+			at := func(minute int) time.Time { return time.Date(2023, 9, 8, 19, minute, 0, 0, time.UTC) }
+			want := []OHLCV{
+				{Timestamp: at(45), Open: 147.63, High: 147.65, Low: 147.63, Close: 147.65, Volume: 30},
+				{Timestamp: at(50), Open: 147.65, High: 147.70, Low: 147.65, Close: 147.70, Volume: 52},
+				{Timestamp: at(55), Open: 147.70, High: 147.70, Low: 147.68, Close: 147.68, Volume: 126},
+			}
+			checkBars(t, intraday.TimeSeries, want)
+		}},
+		{"rsi.json", func(t *testing.T, data []byte) {
+			var rsi IndicatorResponse
+			if err := UnmarshalIndicatorJSON(&rsi, data, "RSI"); err != nil {
+				t.Fatal(err)
+			}
+			checkIndicator(t, rsi, "RSI", []float64{55.0874, 57.9036, 55.2311})
+		}},
+		{"bbands.json", func(t *testing.T, data []byte) {
+			var bbands IndicatorResponse
+			if err := UnmarshalIndicatorJSON(&bbands, data, "BBANDS"); err != nil {
+				t.Fatal(err)
+			}
+			checkIndicator(t, bbands, "Real Upper Band", []float64{147.7561, 147.7528})
+			checkIndicator(t, bbands, "Real Lower Band", []float64{147.5619, 147.5672})
+		}},
+		{"crypto_daily.json", func(t *testing.T, data []byte) {
+			var crypto CryptoSeriesResponse
+			if err := UnmarshalCryptoJSON(&crypto, data); err != nil {
+				t.Fatal(err)
+			}
+			if crypto.MetaData.DigitalCurrencyCode != "BTC" || crypto.MetaData.MarketCode != "EUR" {
+				t.Errorf("unexpected metadata %+v", crypto.MetaData)
+			}
+			if len(crypto.TimeSeries) != 2 {
+				t.Fatalf("got %d bars, want 2", len(crypto.TimeSeries))
+			}
+			// Prices are quoted in the EUR market, not the USD columns next to them
+			got := crypto.TimeSeries[1]
+			if !got.Timestamp.Equal(date(2023, 9, 10)) || got.Open != 24068.6076 || got.Close != 23554.7864 || got.Volume != 22064 {
+				t.Errorf("last bar = %+v", got)
+			}
+		}},
+		{"crypto_intraday.json", func(t *testing.T, data []byte) {
+			var crypto CryptoSeriesResponse
+			if err := UnmarshalCryptoJSON(&crypto, data); err != nil {
+				t.Fatal(err)
+			}
+			if crypto.MetaData.DigitalCurrencyCode != "ETH" || crypto.MetaData.Interval != "5min" {
+				t.Errorf("unexpected metadata %+v", crypto.MetaData)
+			}
+			if len(crypto.TimeSeries) != 2 {
+				t.Fatalf("got %d bars, want 2", len(crypto.TimeSeries))
+			}
+			got := crypto.TimeSeries[1]
+			if !got.Timestamp.Equal(time.Date(2023, 9, 11, 13, 55, 0, 0, time.UTC)) || got.Open != 1561.1 || got.High != 1561.83 || got.Low != 1560.47 || got.Close != 1560.73 || got.Volume != 112 {
+				t.Errorf("last bar = %+v", got)
+			}
+		}},
+		{"crypto_rating.json", func(t *testing.T, data []byte) {
+			var rating CryptoRating
+			if err := json.Unmarshal(data, &rating); err != nil {
+				t.Fatal(err)
+			}
+			if rating.Symbol != "BTC" || rating.FCASRating != "Superb" || rating.FCASScore != 930 || rating.UtilityScore != 981 {
+				t.Errorf("rating = %+v", rating)
+			}
+		}},
+		{"fx_intraday.json", func(t *testing.T, data []byte) {
+			var fx FXTimeSeries
+			if err := json.Unmarshal(data, &fx); err != nil {
+				t.Fatal(err)
+			}
+			if fx.MetaData.FromSymbol != "EUR" || fx.MetaData.ToSymbol != "USD" || fx.MetaData.Interval != "5min" || fx.MetaData.LastRefreshed != "2023-09-08 19:55:00" {
+				t.Errorf("unexpected metadata %+v", fx.MetaData)
+			}
+			if len(fx.TimeSeries) != 2 {
+				t.Fatalf("got %d bars, want 2", len(fx.TimeSeries))
+			}
+			got := fx.TimeSeries[1]
+			if !got.Timestamp.Equal(time.Date(2023, 9, 8, 19, 55, 0, 0, time.UTC)) || got.Open != 1.0701 || got.High != 1.0702 || got.Low != 1.0699 || got.Close != 1.06995 {
+				t.Errorf("last bar = %+v", got)
+			}
+		}},
+		{"fx_daily.json", func(t *testing.T, data []byte) {
+			var fx FXTimeSeries
+			if err := json.Unmarshal(data, &fx); err != nil {
+				t.Fatal(err)
+			}
+			// The daily metadata is numbered differently from the intraday one
+			if fx.MetaData.OutputSize != "Compact" || fx.MetaData.LastRefreshed != "2023-09-08 20:55:00" || fx.MetaData.TimeZone != "UTC" {
+				t.Errorf("unexpected metadata %+v", fx.MetaData)
+			}
+			if len(fx.TimeSeries) != 2 || !fx.TimeSeries[0].Timestamp.Equal(date(2023, 9, 7)) || fx.TimeSeries[1].High != 1.0752 {
+				t.Errorf("unexpected bars %+v", fx.TimeSeries)
+			}
+		}},
+		{"top_gainers_losers.json", func(t *testing.T, data []byte) {
+			var movers MarketMovers
+			if err := json.Unmarshal(data, &movers); err != nil {
+				t.Fatal(err)
+			}
+			// 16:15:59 in New York, on daylight saving time
+			if want := time.Date(2023, 9, 8, 20, 15, 59, 0, time.UTC); !movers.LastUpdated.Equal(want) {
+				t.Errorf("last updated = %v, want %v", movers.LastUpdated, want)
+			}
+			if len(movers.TopGainers) != 2 || len(movers.TopLosers) != 1 || len(movers.MostActivelyTraded) != 1 {
+				t.Fatalf("got %d gainers, %d losers and %d most active, want 2, 1 and 1",
+					len(movers.TopGainers), len(movers.TopLosers), len(movers.MostActivelyTraded))
+			}
+			want := MarketMover{Ticker: "SGBX", Price: 0.0607, ChangeAmount: -0.0553, ChangePercentage: -47.6724, Volume: 55467183}
+			if movers.TopLosers[0] != want {
+				t.Errorf("top loser = %+v, want %+v", movers.TopLosers[0], want)
+			}
+		}},
+		{"wti.json", func(t *testing.T, data []byte) {
+			var wti CommoditySeries
+			if err := json.Unmarshal(data, &wti); err != nil {
+				t.Fatal(err)
+			}
+			if wti.Name != "Crude Oil Prices WTI" || wti.Interval != "monthly" || wti.Unit != "dollars per barrel" {
+				t.Errorf("unexpected metadata %+v", wti)
+			}
+			// The missing June observation is skipped and the rest sorted ascending
+			want := []DataPoint{{date(2023, 5, 1), 71.58}, {date(2023, 7, 1), 76.07}, {date(2023, 8, 1), 81.39}}
+			if !reflect.DeepEqual(wti.Data, want) {
+				t.Errorf("data = %v, want %v", wti.Data, want)
+			}
+		}},
+		{"treasury_yield.json", func(t *testing.T, data []byte) {
+			var yield EconomicSeries
+			if err := json.Unmarshal(data, &yield); err != nil {
+				t.Fatal(err)
+			}
+			if yield.Name != "10-Year Treasury Constant Maturity Rate" || yield.Interval != "daily" || yield.Unit != "percent" {
+				t.Errorf("unexpected metadata %+v", yield)
+			}
+			// Labor Day has no observation
+			want := []DataPoint{{date(2023, 9, 1), 4.18}, {date(2023, 9, 7), 4.27}, {date(2023, 9, 8), 4.26}}
+			if !reflect.DeepEqual(yield.Data, want) {
+				t.Errorf("data = %v, want %v", yield.Data, want)
+			}
+		}},
+		{"analytics_fixed_window.json", func(t *testing.T, data []byte) {
+			var analytics AnalyticsResult
+			if err := json.Unmarshal(data, &analytics); err != nil {
+				t.Fatal(err)
+			}
+			if analytics.MetaData.Symbols != "AAPL,MSFT,IBM" || analytics.MetaData.MinDate != "2023-07-03" || analytics.MetaData.Interval != "DAILY" {
+				t.Errorf("unexpected metadata %+v", analytics.MetaData)
+			}
+			if mean, ok := analytics.Statistic("MEAN", "IBM"); !ok || mean != 0.0011 {
+				t.Errorf("MEAN of IBM = %v, %v, want 0.0011", mean, ok)
+			}
+			if stddev, ok := analytics.Statistic("STDDEV", "MSFT"); !ok || stddev != 0.0133 {
+				t.Errorf("STDDEV of MSFT = %v, %v, want 0.0133", stddev, ok)
+			}
+			correlation, ok := analytics.Correlation()
+			if !ok {
+				t.Fatal("no correlation matrix")
+			}
+			// Only the lower triangle is sent, so the pair is found in either order
+			if got, ok := correlation.At("AAPL", "IBM"); !ok || got != 0.2311 {
+				t.Errorf("correlation of AAPL and IBM = %v, %v, want 0.2311", got, ok)
+			}
+			if got, ok := correlation.At("IBM", "MSFT"); !ok || got != 0.3012 {
+				t.Errorf("correlation of IBM and MSFT = %v, %v, want 0.3012", got, ok)
+			}
+		}},
+		{"market_status.json", func(t *testing.T, data []byte) {
+			var status MarketStatus
+			if err := json.Unmarshal(data, &status); err != nil {
+				t.Fatal(err)
+			}
+			if status.Endpoint != "Global Market Open & Close Status" || len(status.Markets) != 3 {
+				t.Fatalf("got %q with %d markets, want 3", status.Endpoint, len(status.Markets))
+			}
+			us := status.Markets[0]
+			if us.PrimaryExchanges != "NASDAQ, NYSE, AMEX, BATS" || us.LocalOpen.Format("15:04") != "09:30" || us.LocalClose.Format("15:04") != "16:15" {
+				t.Errorf("unexpected US market %+v", us)
+			}
+			if !status.IsOpen("united states") || status.IsOpen("Japan") || status.IsOpen("Mars") {
+				t.Errorf("unexpected open regions in %+v", status.Markets)
+			}
+		}},
+		{"splits.json", func(t *testing.T, data []byte) {
+			var splits []Split
+			if err := UnmarshalSplitsJSON(&splits, data); err != nil {
+				t.Fatal(err)
+			}
+			want := []Split{{date(1979, 6, 1), 4}, {date(1997, 5, 28), 2}, {date(1999, 5, 27), 2}}
+			if !reflect.DeepEqual(splits, want) {
+				t.Errorf("splits = %v, want %v", splits, want)
+			}
+		}},
+		{"dividends.json", func(t *testing.T, data []byte) {
+			var dividends []Dividend
+			if err := UnmarshalDividendsJSON(&dividends, data); err != nil {
+				t.Fatal(err)
+			}
+			if len(dividends) != 2 {
+				t.Fatalf("got %d dividends, want 2", len(dividends))
+			}
+			// Oldest first, with the "None" declaration date left zero
+			want := Dividend{ExDividendDate: date(2023, 8, 9), RecordDate: date(2023, 8, 10), PaymentDate: date(2023, 9, 9), Amount: 1.66}
+			if dividends[0] != want {
+				t.Errorf("first dividend = %+v, want %+v", dividends[0], want)
+			}
+			if !dividends[1].DeclarationDate.Equal(date(2023, 10, 31)) {
+				t.Errorf("second dividend = %+v", dividends[1])
+			}
+		}},
+		{"historical_options.json", func(t *testing.T, data []byte) {
+			var chain OptionsChain
+			if err := json.Unmarshal(data, &chain); err != nil {
+				t.Fatal(err)
+			}
+			if chain.Endpoint != "Historical Options" || chain.Message != "success" || len(chain.Contracts) != 3 {
+				t.Fatalf("got %q with %d contracts, want 3", chain.Endpoint, len(chain.Contracts))
+			}
+			// Sorted by expiration, then strike
+			ids := []string{chain.Contracts[0].ContractID, chain.Contracts[1].ContractID, chain.Contracts[2].ContractID}
+			if want := []string{"IBM230915C00145000", "IBM230915C00150000", "IBM231020P00150000"}; !reflect.DeepEqual(ids, want) {
+				t.Errorf("contracts = %v, want %v", ids, want)
+			}
+			put := chain.Contracts[2]
+			if put.Type != "put" || !put.Expiration.Equal(date(2023, 10, 20)) || !put.Date.Equal(date(2023, 9, 8)) ||
+				put.Strike != 150 || put.Bid != 4 || put.AskSize != 15 || put.OpenInterest != 3871 || put.Delta != -0.55931 || put.Rho != -0.08853 {
+				t.Errorf("put = %+v", put)
+			}
+		}},
+		{"news.json", func(t *testing.T, data []byte) {
+			var news NewsSentiment
+			if err := json.Unmarshal(data, &news); err != nil {
+				t.Fatal(err)
+			}
+			if news.Items != 1 || len(news.Feed) != 1 {
+				t.Fatalf("got %d items and %d articles, want 1 and 1", news.Items, len(news.Feed))
+			}
+			article := news.Feed[0]
+			if !article.TimePublished.Equal(time.Date(2023, 9, 8, 16, 15, 0, 0, time.UTC)) || len(article.Topics) != 2 {
+				t.Errorf("article = %+v", article)
+			}
+			if len(article.TickerSentiment) != 1 || article.TickerSentiment[0].Ticker != "IBM" || article.TickerSentiment[0].SentimentScore != 0.31 {
+				t.Errorf("ticker sentiment = %+v", article.TickerSentiment)
+			}
+		}},
+		{"quote_sparse.json", func(t *testing.T, data []byte) {
+			var quote Quote
+			if err := json.Unmarshal(data, &quote); err != nil {
+				t.Fatal(err)
+			}
+			if quote.Symbol != "NEWCO" || quote.Price != 22.87 || quote.Volume != 15872345 || quote.PreviousClose != 0 {
+				t.Errorf("quote = %+v", quote)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			tt.check(t, readFixture(t, tt.fixture))
+		})
+	}
+}
+
+func checkBars(t *testing.T, got, want []OHLCV) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d bars, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bar %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func checkIndicator(t *testing.T, got IndicatorResponse, name string, want []float64) {
+	t.Helper()
+	if len(got.IndicatorValues) != len(want) {
+		t.Fatalf("got %d values, want %d", len(got.IndicatorValues), len(want))
+	}
+	for i, value := range got.IndicatorValues {
+		if i > 0 && !value.Timestamp.After(got.IndicatorValues[i-1].Timestamp) {
+			t.Errorf("values are not in ascending order at %d", i)
+		}
+		if value.Values[name] != want[i] {
+			t.Errorf("%s %d = %v, want %v", name, i, value.Values[name], want[i])
+		}
+	}
+}
+
+// TestUnmarshalTwice checks that unmarshaling a second response into a value that already
+// holds one leaves nothing of the first: neither its bars nor its metadata.
+func TestUnmarshalTwice(t *testing.T) {
+	t.Run("daily", func(t *testing.T) {
+		var daily TimeSeriesDaily
+		if err := json.Unmarshal(readFixture(t, "daily.json"), &daily); err != nil {
+			t.Fatal(err)
+		}
+		second := `{"Time Series (Daily)": {"2024-01-02": {"1. open": "1", "2. high": "2", "3. low": "0.5", "4. close": "1.5", "5. volume": "10"}}}`
+		if err := json.Unmarshal([]byte(second), &daily); err != nil {
+			t.Fatal(err)
+		}
+		checkBars(t, daily.TimeSeries, []OHLCV{{Timestamp: date(2024, 1, 2), Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10}})
+		if daily.MetaData != (TimeSeriesMetaData{}) {
+			t.Errorf("metadata of the first response kept: %+v", daily.MetaData)
+		}
+	})
+
+	t.Run("intraday", func(t *testing.T) {
+		var intraday TimeSeriesIntraday
+		if err := json.Unmarshal(readFixture(t, "intraday.json"), &intraday); err != nil {
+			t.Fatal(err)
+		}
+		second := `{"Time Series (5min)": {"2024-01-02 09:35:00": {"1. open": "1", "2. high": "1", "3. low": "1", "4. close": "1", "5. volume": "1"}}}`
+		if err := json.Unmarshal([]byte(second), &intraday); err != nil {
+			t.Fatal(err)
+		}
+		if len(intraday.TimeSeries) != 1 || intraday.MetaData.Symbol != "" {
+			t.Errorf("got %d bars and symbol %q, want 1 bar and no symbol", len(intraday.TimeSeries), intraday.MetaData.Symbol)
+		}
+	})
+
+	t.Run("indicator", func(t *testing.T) {
+		var rsi IndicatorResponse
+		if err := UnmarshalIndicatorJSON(&rsi, readFixture(t, "rsi.json"), "RSI"); err != nil {
+			t.Fatal(err)
+		}
+		second := `{"Technical Analysis: RSI": {"2024-01-02 09:35": {"RSI": "50.0000"}}}`
+		if err := UnmarshalIndicatorJSON(&rsi, []byte(second), "RSI"); err != nil {
+			t.Fatal(err)
+		}
+		checkIndicator(t, rsi, "RSI", []float64{50})
+		if rsi.MetaData.Symbol != "" {
+			t.Errorf("symbol of the first response kept: %q", rsi.MetaData.Symbol)
+		}
+	})
+
+	t.Run("crypto", func(t *testing.T) {
+		var crypto CryptoSeriesResponse
+		if err := UnmarshalCryptoJSON(&crypto, readFixture(t, "crypto_daily.json")); err != nil {
+			t.Fatal(err)
+		}
+		if err := UnmarshalCryptoJSON(&crypto, cryptoBody("USD", "USD")); err != nil {
+			t.Fatal(err)
+		}
+		if len(crypto.TimeSeries) != 1 || crypto.MetaData.DigitalCurrencyCode != "" {
+			t.Errorf("got %d bars and code %q, want 1 bar and no code", len(crypto.TimeSeries), crypto.MetaData.DigitalCurrencyCode)
+		}
+	})
+
+	t.Run("fx", func(t *testing.T) {
+		var fx FXTimeSeries
+		first := `{"Meta Data": {"2. From Symbol": "EUR", "3. To Symbol": "USD"}, "Time Series FX (Daily)": {"2024-01-02": {"1. open": "1.1", "2. high": "1.2", "3. low": "1.0", "4. close": "1.1"}, "2024-01-03": {"1. open": "1.1", "2. high": "1.2", "3. low": "1.0", "4. close": "1.1"}}}`
+		second := `{"Time Series FX (Daily)": {"2024-01-04": {"1. open": "1.1", "2. high": "1.2", "3. low": "1.0", "4. close": "1.1"}}}`
+		if err := json.Unmarshal([]byte(first), &fx); err != nil {
+			t.Fatal(err)
+		}
+		if len(fx.TimeSeries) != 2 || fx.MetaData.FromSymbol != "EUR" {
+			t.Fatalf("first response decoded as %+v", fx)
+		}
+		if err := json.Unmarshal([]byte(second), &fx); err != nil {
+			t.Fatal(err)
+		}
+		if len(fx.TimeSeries) != 1 || fx.MetaData != (FXMetaData{}) {
+			t.Errorf("got %d bars and metadata %+v, want 1 bar and no metadata", len(fx.TimeSeries), fx.MetaData)
+		}
+	})
+}
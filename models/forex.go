@@ -0,0 +1,183 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage forex data.
+//
+// This file contains types and functions representing the interactions and responses 
+// for foreign exchange (FX) time series provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FXParams represents the parameters for querying FX time series data.
+type FXParams struct {
+	FromSymbol string
+	ToSymbol   string
+	Interval   string
+	OutputSize OutputSize
+	DataType   DataType
+}
+
+// FXMetaData represents the metadata for the FX time series data.
+type FXMetaData struct {
+	Information   string
+	FromSymbol    string
+	ToSymbol      string
+	LastRefreshed string
+	Interval      string
+	OutputSize    string
+	TimeZone      string
+}
+
+// OHLC represents the Open, High, Low, and Close data for a given timestamp.
+// FX series carry no volume.
+type OHLC struct {
+	Timestamp time.Time `json:"-"`
+	Open      float64   `json:"1. open,string"`
+	High      float64   `json:"2. high,string"`
+	Low       float64   `json:"3. low,string"`
+	Close     float64   `json:"4. close,string"`
+}
+
+// FXTimeSeries represents the response for the FX intraday, daily, weekly and monthly data.
+type FXTimeSeries struct {
+	MetaData   FXMetaData
+	TimeSeries []OHLC
+}
+
+// Reset clears the FXTimeSeries so that it can be reused.
+func (f *FXTimeSeries) Reset() {
+	*f = FXTimeSeries{}
+}
+
+// UnmarshalJSON is a custom unmarshaler for the FXTimeSeries struct.
+func (f *FXTimeSeries) UnmarshalJSON(data []byte) error {
+	// Start from scratch so that bars of a previous response do not accumulate
+	f.Reset()
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if rawMetaData, ok := raw["Meta Data"]; ok {
+		var metaData map[string]string
+		if err := json.Unmarshal(rawMetaData, &metaData); err != nil {
+			return err
+		}
+		f.MetaData = extractFXMetaData(metaData)
+	}
+
+	for key, value := range raw {
+		if !strings.HasPrefix(key, "Time Series FX") {
+			continue
+		}
+
+		var tsData map[string]OHLC
+		if err := json.Unmarshal(value, &tsData); err != nil {
+			return err
+		}
+
+		for k, ohlc := range tsData {
+			timestamp, err := parseFXTimestamp(k)
+			if err != nil {
+				return err
+			}
+			ohlc.Timestamp = timestamp
+			f.TimeSeries = append(f.TimeSeries, ohlc)
+		}
+	}
+
+	sort.SliceStable(f.TimeSeries, func(i, j int) bool {
+		return f.TimeSeries[i].Timestamp.Before(f.TimeSeries[j].Timestamp)
+	})
+
+	return nil
+}
+
+// extractFXMetaData maps the metadata block onto FXMetaData. The numbering of the
+// keys differs between the intraday and the daily/weekly/monthly responses, so only
+// the label after the number is matched.
+func extractFXMetaData(rawData map[string]string) FXMetaData {
+	var metaData FXMetaData
+
+	for key, value := range rawData {
+		if idx := strings.Index(key, ". "); idx >= 0 {
+			key = key[idx+2:]
+		}
+
+		switch key {
+		case "Information":
+			metaData.Information = value
+		case "From Symbol":
+			metaData.FromSymbol = value
+		case "To Symbol":
+			metaData.ToSymbol = value
+		case "Last Refreshed":
+			metaData.LastRefreshed = value
+		case "Interval":
+			metaData.Interval = value
+		case "Output Size":
+			metaData.OutputSize = value
+		case "Time Zone":
+			metaData.TimeZone = value
+		}
+	}
+	return metaData
+}
+
+// parseFXTimestamp parses both the intraday datetime and the plain date keys.
+func parseFXTimestamp(value string) (time.Time, error) {
+	if len(value) > len("2006-01-02") {
+		return time.Parse("2006-01-02 15:04:05", value)
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// Length returns the count of time series data entries.
+func (f *FXTimeSeries) Length() int {
+	return len(f.TimeSeries)
+}
+
+// String representation of the FXTimeSeries for custom printing.
+func (f FXTimeSeries) String() string {
+	var sb strings.Builder
+	prec := precision(4)
+
+	// First, print metadata
+	sb.WriteString(f.MetaData.Information + "\n")
+	sb.WriteString(fmt.Sprintf("From: %s\n", f.MetaData.FromSymbol))
+	sb.WriteString(fmt.Sprintf("To: %s\n", f.MetaData.ToSymbol))
+	sb.WriteString(fmt.Sprintf("Last Refreshed: %s\n", f.MetaData.LastRefreshed))
+	if f.MetaData.Interval != "" {
+		sb.WriteString(fmt.Sprintf("Interval: %s\n", f.MetaData.Interval))
+	}
+	sb.WriteString(fmt.Sprintf("Output Size: %s\n", f.MetaData.OutputSize))
+	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", f.MetaData.TimeZone))
+	sb.WriteString("\n")
+
+	// Column widths follow the data so large rates stay aligned
+	headers := []string{"Time", "Open", "High", "Low", "Close"}
+	rows := make([][]string, 0, len(f.TimeSeries))
+	for _, v := range f.TimeSeries {
+		rows = append(rows, []string{
+			v.Timestamp.Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%.*f", prec, v.Open),
+			fmt.Sprintf("%.*f", prec, v.High),
+			fmt.Sprintf("%.*f", prec, v.Low),
+			fmt.Sprintf("%.*f", prec, v.Close),
+		})
+	}
+	writeTable(&sb, headers, rows)
+
+	return sb.String()
+}
@@ -0,0 +1,284 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage fundamental data.
+//
+// This file contains types and functions representing the interactions and responses
+// for the financial statements provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IncomeStatement represents the response for the INCOME_STATEMENT endpoint.
+type IncomeStatement struct {
+	Symbol           string
+	AnnualReports    []IncomeStatementReport
+	QuarterlyReports []IncomeStatementReport
+}
+
+// IncomeStatementReport represents a single annual or quarterly income statement.
+// Values reported as "None" or "-" are NaN.
+type IncomeStatementReport struct {
+	FiscalDateEnding                  time.Time `json:"fiscalDateEnding"`
+	ReportedCurrency                  string    `json:"reportedCurrency"`
+	GrossProfit                       float64   `json:"grossProfit"`
+	TotalRevenue                      float64   `json:"totalRevenue"`
+	CostOfRevenue                     float64   `json:"costOfRevenue"`
+	CostOfGoodsAndServicesSold        float64   `json:"costofGoodsAndServicesSold"`
+	OperatingIncome                   float64   `json:"operatingIncome"`
+	SellingGeneralAndAdministrative   float64   `json:"sellingGeneralAndAdministrative"`
+	ResearchAndDevelopment            float64   `json:"researchAndDevelopment"`
+	OperatingExpenses                 float64   `json:"operatingExpenses"`
+	InvestmentIncomeNet               float64   `json:"investmentIncomeNet"`
+	NetInterestIncome                 float64   `json:"netInterestIncome"`
+	InterestIncome                    float64   `json:"interestIncome"`
+	InterestExpense                   float64   `json:"interestExpense"`
+	NonInterestIncome                 float64   `json:"nonInterestIncome"`
+	OtherNonOperatingIncome           float64   `json:"otherNonOperatingIncome"`
+	Depreciation                      float64   `json:"depreciation"`
+	DepreciationAndAmortization       float64   `json:"depreciationAndAmortization"`
+	IncomeBeforeTax                   float64   `json:"incomeBeforeTax"`
+	IncomeTaxExpense                  float64   `json:"incomeTaxExpense"`
+	InterestAndDebtExpense            float64   `json:"interestAndDebtExpense"`
+	NetIncomeFromContinuingOperations float64   `json:"netIncomeFromContinuingOperations"`
+	ComprehensiveIncomeNetOfTax       float64   `json:"comprehensiveIncomeNetOfTax"`
+	EBIT                              float64   `json:"ebit"`
+	EBITDA                            float64   `json:"ebitda"`
+	NetIncome                         float64   `json:"netIncome"`
+}
+
+// BalanceSheet represents the response for the BALANCE_SHEET endpoint.
+type BalanceSheet struct {
+	Symbol           string
+	AnnualReports    []BalanceSheetReport
+	QuarterlyReports []BalanceSheetReport
+}
+
+// BalanceSheetReport represents a single annual or quarterly balance sheet.
+// Values reported as "None" or "-" are NaN.
+type BalanceSheetReport struct {
+	FiscalDateEnding                       time.Time `json:"fiscalDateEnding"`
+	ReportedCurrency                       string    `json:"reportedCurrency"`
+	TotalAssets                            float64   `json:"totalAssets"`
+	TotalCurrentAssets                     float64   `json:"totalCurrentAssets"`
+	CashAndCashEquivalentsAtCarryingValue  float64   `json:"cashAndCashEquivalentsAtCarryingValue"`
+	CashAndShortTermInvestments            float64   `json:"cashAndShortTermInvestments"`
+	Inventory                              float64   `json:"inventory"`
+	CurrentNetReceivables                  float64   `json:"currentNetReceivables"`
+	TotalNonCurrentAssets                  float64   `json:"totalNonCurrentAssets"`
+	PropertyPlantEquipment                 float64   `json:"propertyPlantEquipment"`
+	AccumulatedDepreciationAmortizationPPE float64   `json:"accumulatedDepreciationAmortizationPPE"`
+	IntangibleAssets                       float64   `json:"intangibleAssets"`
+	IntangibleAssetsExcludingGoodwill      float64   `json:"intangibleAssetsExcludingGoodwill"`
+	Goodwill                               float64   `json:"goodwill"`
+	Investments                            float64   `json:"investments"`
+	LongTermInvestments                    float64   `json:"longTermInvestments"`
+	ShortTermInvestments                   float64   `json:"shortTermInvestments"`
+	OtherCurrentAssets                     float64   `json:"otherCurrentAssets"`
+	OtherNonCurrentAssets                  float64   `json:"otherNonCurrentAssets"`
+	TotalLiabilities                       float64   `json:"totalLiabilities"`
+	TotalCurrentLiabilities                float64   `json:"totalCurrentLiabilities"`
+	CurrentAccountsPayable                 float64   `json:"currentAccountsPayable"`
+	DeferredRevenue                        float64   `json:"deferredRevenue"`
+	CurrentDebt                            float64   `json:"currentDebt"`
+	ShortTermDebt                          float64   `json:"shortTermDebt"`
+	TotalNonCurrentLiabilities             float64   `json:"totalNonCurrentLiabilities"`
+	CapitalLeaseObligations                float64   `json:"capitalLeaseObligations"`
+	LongTermDebt                           float64   `json:"longTermDebt"`
+	CurrentLongTermDebt                    float64   `json:"currentLongTermDebt"`
+	LongTermDebtNoncurrent                 float64   `json:"longTermDebtNoncurrent"`
+	ShortLongTermDebtTotal                 float64   `json:"shortLongTermDebtTotal"`
+	OtherCurrentLiabilities                float64   `json:"otherCurrentLiabilities"`
+	OtherNonCurrentLiabilities             float64   `json:"otherNonCurrentLiabilities"`
+	TotalShareholderEquity                 float64   `json:"totalShareholderEquity"`
+	TreasuryStock                          float64   `json:"treasuryStock"`
+	RetainedEarnings                       float64   `json:"retainedEarnings"`
+	CommonStock                            float64   `json:"commonStock"`
+	CommonStockSharesOutstanding           float64   `json:"commonStockSharesOutstanding"`
+}
+
+// CashFlow represents the response for the CASH_FLOW endpoint.
+type CashFlow struct {
+	Symbol           string
+	AnnualReports    []CashFlowReport
+	QuarterlyReports []CashFlowReport
+}
+
+// CashFlowReport represents a single annual or quarterly cash flow statement.
+// Values reported as "None" or "-" are NaN.
+type CashFlowReport struct {
+	FiscalDateEnding                                          time.Time `json:"fiscalDateEnding"`
+	ReportedCurrency                                          string    `json:"reportedCurrency"`
+	OperatingCashflow                                         float64   `json:"operatingCashflow"`
+	PaymentsForOperatingActivities                            float64   `json:"paymentsForOperatingActivities"`
+	ProceedsFromOperatingActivities                           float64   `json:"proceedsFromOperatingActivities"`
+	ChangeInOperatingLiabilities                              float64   `json:"changeInOperatingLiabilities"`
+	ChangeInOperatingAssets                                   float64   `json:"changeInOperatingAssets"`
+	DepreciationDepletionAndAmortization                      float64   `json:"depreciationDepletionAndAmortization"`
+	CapitalExpenditures                                       float64   `json:"capitalExpenditures"`
+	ChangeInReceivables                                       float64   `json:"changeInReceivables"`
+	ChangeInInventory                                         float64   `json:"changeInInventory"`
+	ProfitLoss                                                float64   `json:"profitLoss"`
+	CashflowFromInvestment                                    float64   `json:"cashflowFromInvestment"`
+	CashflowFromFinancing                                     float64   `json:"cashflowFromFinancing"`
+	ProceedsFromRepaymentsOfShortTermDebt                     float64   `json:"proceedsFromRepaymentsOfShortTermDebt"`
+	PaymentsForRepurchaseOfCommonStock                        float64   `json:"paymentsForRepurchaseOfCommonStock"`
+	PaymentsForRepurchaseOfEquity                             float64   `json:"paymentsForRepurchaseOfEquity"`
+	PaymentsForRepurchaseOfPreferredStock                     float64   `json:"paymentsForRepurchaseOfPreferredStock"`
+	DividendPayout                                            float64   `json:"dividendPayout"`
+	DividendPayoutCommonStock                                 float64   `json:"dividendPayoutCommonStock"`
+	DividendPayoutPreferredStock                              float64   `json:"dividendPayoutPreferredStock"`
+	ProceedsFromIssuanceOfCommonStock                         float64   `json:"proceedsFromIssuanceOfCommonStock"`
+	ProceedsFromIssuanceOfLongTermDebtAndCapitalSecuritiesNet float64   `json:"proceedsFromIssuanceOfLongTermDebtAndCapitalSecuritiesNet"`
+	ProceedsFromIssuanceOfPreferredStock                      float64   `json:"proceedsFromIssuanceOfPreferredStock"`
+	ProceedsFromRepurchaseOfEquity                            float64   `json:"proceedsFromRepurchaseOfEquity"`
+	ProceedsFromSaleOfTreasuryStock                           float64   `json:"proceedsFromSaleOfTreasuryStock"`
+	ChangeInCashAndCashEquivalents                            float64   `json:"changeInCashAndCashEquivalents"`
+	ChangeInExchangeRate                                      float64   `json:"changeInExchangeRate"`
+	NetIncome                                                 float64   `json:"netIncome"`
+}
+
+// parseFundamentalReports decodes the shape shared by all financial statement responses: the
+// symbol and the annual and quarterly reports, each a map of field name to string value.
+func parseFundamentalReports(data []byte) (symbol string, annual, quarterly []map[string]string, err error) {
+	var raw struct {
+		Symbol           string              `json:"symbol"`
+		AnnualReports    []map[string]string `json:"annualReports"`
+		QuarterlyReports []map[string]string `json:"quarterlyReports"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "", nil, nil, err
+	}
+	return raw.Symbol, raw.AnnualReports, raw.QuarterlyReports, nil
+}
+
+// decodeReports decodes each raw report into a T with decodeReport and sorts the reports
+// newest first by the date returned by fiscalDate.
+func decodeReports[T any](raw []map[string]string, fiscalDate func(T) time.Time) ([]T, error) {
+	reports := make([]T, len(raw))
+	for i, report := range raw {
+		if err := decodeReport(report, &reports[i]); err != nil {
+			return nil, err
+		}
+	}
+	sortReportsDescending(reports, fiscalDate)
+	return reports, nil
+}
+
+// UnmarshalJSON is a custom unmarshaler for the IncomeStatement struct.
+func (s *IncomeStatement) UnmarshalJSON(data []byte) error {
+	symbol, annual, quarterly, err := parseFundamentalReports(data)
+	if err != nil {
+		return err
+	}
+
+	fiscalDate := func(r IncomeStatementReport) time.Time { return r.FiscalDateEnding }
+	s.Symbol = symbol
+	if s.AnnualReports, err = decodeReports(annual, fiscalDate); err != nil {
+		return err
+	}
+	if s.QuarterlyReports, err = decodeReports(quarterly, fiscalDate); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnmarshalJSON is a custom unmarshaler for the BalanceSheet struct.
+func (s *BalanceSheet) UnmarshalJSON(data []byte) error {
+	symbol, annual, quarterly, err := parseFundamentalReports(data)
+	if err != nil {
+		return err
+	}
+
+	fiscalDate := func(r BalanceSheetReport) time.Time { return r.FiscalDateEnding }
+	s.Symbol = symbol
+	if s.AnnualReports, err = decodeReports(annual, fiscalDate); err != nil {
+		return err
+	}
+	if s.QuarterlyReports, err = decodeReports(quarterly, fiscalDate); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnmarshalJSON is a custom unmarshaler for the CashFlow struct.
+func (s *CashFlow) UnmarshalJSON(data []byte) error {
+	symbol, annual, quarterly, err := parseFundamentalReports(data)
+	if err != nil {
+		return err
+	}
+
+	fiscalDate := func(r CashFlowReport) time.Time { return r.FiscalDateEnding }
+	s.Symbol = symbol
+	if s.AnnualReports, err = decodeReports(annual, fiscalDate); err != nil {
+		return err
+	}
+	if s.QuarterlyReports, err = decodeReports(quarterly, fiscalDate); err != nil {
+		return err
+	}
+	return nil
+}
+
+// decodeReport copies the string values of a report onto the fields of the struct pointed
+// to by dst, matching on the json tag. Fields may be string, float64 or time.Time (a date).
+func decodeReport(raw map[string]string, dst interface{}) error {
+	v := reflect.ValueOf(dst).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value, ok := raw[field.Tag.Get("json")]
+		if !ok {
+			continue
+		}
+
+		switch v.Field(i).Interface().(type) {
+		case string:
+			v.Field(i).SetString(value)
+		case float64:
+			number, err := parseFinancialFloat(value)
+			if err != nil {
+				return fmt.Errorf("error parsing '%s': %v", field.Tag.Get("json"), err)
+			}
+			v.Field(i).SetFloat(number)
+		case time.Time:
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return fmt.Errorf("error parsing '%s': %v", field.Tag.Get("json"), err)
+			}
+			v.Field(i).Set(reflect.ValueOf(date))
+		}
+	}
+
+	return nil
+}
+
+// parseFinancialFloat parses a numeric value delivered as a string, such as "1234567",
+// "1,234,567" or "1.2E9". Thousands separators are stripped and the "None" and "-" placeholders
+// of missing values map to NaN.
+func parseFinancialFloat(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "None" || value == "-" {
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+}
+
+// sortReportsDescending sorts reports newest first, as the API returns them.
+func sortReportsDescending[T any](reports []T, fiscalDate func(T) time.Time) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		return fiscalDate(reports[i]).After(fiscalDate(reports[j]))
+	})
+}
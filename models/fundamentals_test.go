@@ -0,0 +1,135 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseFinancialFloat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantNaN bool
+		wantErr bool
+	}{
+		{in: "1234567", want: 1234567},
+		{in: "1,234,567", want: 1234567},
+		{in: "1.2E9", want: 1.2e9},
+		{in: " -42.5 ", want: -42.5},
+		{in: "None", wantNaN: true},
+		{in: "-", wantNaN: true},
+		{in: "n/a", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseFinancialFloat(tt.in)
+		switch {
+		case tt.wantErr:
+			if err == nil {
+				t.Errorf("parseFinancialFloat(%q) = %v, want an error", tt.in, got)
+			}
+		case err != nil:
+			t.Errorf("parseFinancialFloat(%q): %v", tt.in, err)
+		case tt.wantNaN:
+			if !math.IsNaN(got) {
+				t.Errorf("parseFinancialFloat(%q) = %v, want NaN", tt.in, got)
+			}
+		case got != tt.want:
+			t.Errorf("parseFinancialFloat(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// statementBody builds a financial statement response for IBM with two annual reports, listed
+// oldest first, and one quarterly report, each carrying the given fields.
+func statementBody(fields string) []byte {
+	report := func(date string) string {
+		return `{"fiscalDateEnding": "` + date + `", "reportedCurrency": "USD", ` + fields + `}`
+	}
+	return []byte(`{"symbol": "IBM", "annualReports": [` + report("2021-12-31") + `, ` + report("2022-12-31") + `],
+		"quarterlyReports": [` + report("2023-06-30") + `]}`)
+}
+
+// checkReports checks the number of reports and that they are sorted newest first.
+func checkReports(t *testing.T, dates []time.Time, want int) {
+	t.Helper()
+	if len(dates) != want {
+		t.Fatalf("got %d reports, want %d", len(dates), want)
+	}
+	for i := 1; i < len(dates); i++ {
+		if !dates[i-1].After(dates[i]) {
+			t.Errorf("reports not sorted newest first: %v", dates)
+		}
+	}
+}
+
+func TestIncomeStatementUnmarshal(t *testing.T) {
+	var s IncomeStatement
+	body := statementBody(`"grossProfit": "32,687,000,000", "totalRevenue": "6.0530E10", "researchAndDevelopment": "None"`)
+	if err := json.Unmarshal(body, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Symbol != "IBM" {
+		t.Errorf("symbol = %q, want IBM", s.Symbol)
+	}
+	checkReports(t, []time.Time{s.AnnualReports[0].FiscalDateEnding, s.AnnualReports[1].FiscalDateEnding}, 2)
+	if len(s.QuarterlyReports) != 1 {
+		t.Fatalf("got %d quarterly reports, want 1", len(s.QuarterlyReports))
+	}
+
+	r := s.AnnualReports[0]
+	if !r.FiscalDateEnding.Equal(date(2022, 12, 31)) || r.ReportedCurrency != "USD" || r.GrossProfit != 32687000000 || r.TotalRevenue != 60530000000 {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if !math.IsNaN(r.ResearchAndDevelopment) {
+		t.Errorf("researchAndDevelopment = %v, want NaN for None", r.ResearchAndDevelopment)
+	}
+}
+
+func TestBalanceSheetUnmarshal(t *testing.T) {
+	var s BalanceSheet
+	body := statementBody(`"totalAssets": "127243000000", "inventory": "1,552,000,000", "goodwill": "None"`)
+	if err := json.Unmarshal(body, &s); err != nil {
+		t.Fatal(err)
+	}
+	checkReports(t, []time.Time{s.AnnualReports[0].FiscalDateEnding, s.AnnualReports[1].FiscalDateEnding}, 2)
+	if len(s.QuarterlyReports) != 1 {
+		t.Fatalf("got %d quarterly reports, want 1", len(s.QuarterlyReports))
+	}
+
+	r := s.QuarterlyReports[0]
+	if !r.FiscalDateEnding.Equal(date(2023, 6, 30)) || r.TotalAssets != 127243000000 || r.Inventory != 1552000000 {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if !math.IsNaN(r.Goodwill) {
+		t.Errorf("goodwill = %v, want NaN for None", r.Goodwill)
+	}
+}
+
+func TestCashFlowUnmarshal(t *testing.T) {
+	var s CashFlow
+	body := statementBody(`"operatingCashflow": "10435000000", "dividendPayout": "-", "changeInInventory": "None"`)
+	if err := json.Unmarshal(body, &s); err != nil {
+		t.Fatal(err)
+	}
+	checkReports(t, []time.Time{s.AnnualReports[0].FiscalDateEnding, s.AnnualReports[1].FiscalDateEnding}, 2)
+
+	r := s.AnnualReports[1]
+	if !r.FiscalDateEnding.Equal(date(2021, 12, 31)) || r.OperatingCashflow != 10435000000 {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if !math.IsNaN(r.DividendPayout) || !math.IsNaN(r.ChangeInInventory) {
+		t.Errorf("dividendPayout = %v, changeInInventory = %v, want NaN", r.DividendPayout, r.ChangeInInventory)
+	}
+}
+
+func TestStatementUnmarshalMalformed(t *testing.T) {
+	var s IncomeStatement
+	if err := json.Unmarshal(statementBody(`"netIncome": "lots"`), &s); err == nil {
+		t.Error("malformed value: expected an error")
+	}
+	if err := json.Unmarshal([]byte(`{"symbol": "IBM", "annualReports": [{"fiscalDateEnding": "2022"}]}`), &s); err == nil {
+		t.Error("malformed date: expected an error")
+	}
+}
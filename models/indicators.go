@@ -11,6 +11,8 @@ Author: Mason Wheeler
 package models
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"time"
 	"fmt"
@@ -20,18 +22,61 @@ import (
 )
 
 type IndicatorParams struct {
-	Function   string
-	Symbol     string
-	Interval   string
-	TimePeriod int
-	SeriesType string
-	Month      string
-	OutputSize string
-	DataType   string
+	Function    string
+	Symbol      string
+	Interval    Interval
+	TimePeriod  int
+	SeriesType  SeriesType // defaults to close when empty
+	Month       string     // YYYY-MM, intraday intervals only
+	OutputSize  OutputSize
+	DataType    DataType
+	Entitlement string // "realtime" or "delayed" for premium plans; empty leaves the API default
+
+	// Optional tuning, sent only when non-zero
+	FastPeriod   int     // MACD, MACDEXT, APO, PPO
+	SlowPeriod   int     // MACD, MACDEXT, APO, PPO
+	SignalPeriod int     // MACD, MACDEXT
+	FastLimit    float64 // MAMA
+	SlowLimit    float64 // MAMA
+
+	// Stochastic smoothing, sent only when non-zero. The MA types take the API's
+	// 0 (SMA) to 8 (MAMA) codes; 0 is also the API default.
+	FastKPeriod int // STOCH, STOCHF, STOCHRSI
+	SlowKPeriod int // STOCH
+	SlowDPeriod int // STOCH
+	SlowKMAType int // STOCH
+	SlowDMAType int // STOCH
+	FastDPeriod int // STOCHF, STOCHRSI
+	FastDMAType int // STOCHF, STOCHRSI
+}
+
+// Validate checks the parameters the API would otherwise answer with an opaque error message,
+// or silently ignore. An empty SeriesType is accepted as it defaults to close. Month selects a
+// slice of intraday history, so it must be a YYYY-MM month and the interval an intraday one.
+func (p IndicatorParams) Validate() error {
+	if p.Interval != "" && !p.Interval.IsValid() {
+		return invalidIntervalError(p.Interval)
+	}
+	if p.SeriesType != "" && !p.SeriesType.IsValid() {
+		return fmt.Errorf("invalid series type %q: must be close, open, high or low", p.SeriesType)
+	}
+	if p.OutputSize != "" && !p.OutputSize.IsValid() {
+		return fmt.Errorf("invalid output size %q: must be compact or full", p.OutputSize)
+	}
+	if p.Month != "" {
+		if !p.Interval.IsIntraday() {
+			return fmt.Errorf("month is only supported for intraday intervals (1min to 60min), got %q", p.Interval)
+		}
+		if _, err := time.Parse("2006-01", p.Month); err != nil {
+			return fmt.Errorf("invalid month %q: must be formatted as YYYY-MM", p.Month)
+		}
+	}
+	return nil
 }
 
 type IndicatorResponse struct {
 	MetaData   TimeSeriesMetaData `json:"Meta Data"`
+	Function   string             `json:"-"` // the function name the response was parsed for, e.g. "RSI"
 	IndicatorValues  []IndicatorValue   `json:"-"`
 }
 
@@ -40,7 +85,20 @@ type IndicatorValue struct {
     Values    map[string]float64   `json:"-"`
 }
 
+// IndicatorPoint represents a single value of one indicator output at a given timestamp.
+type IndicatorPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// Reset clears the IndicatorResponse so that it can be reused.
+func (i *IndicatorResponse) Reset() {
+	*i = IndicatorResponse{}
+}
+
 func UnmarshalIndicatorJSON(i *IndicatorResponse, data []byte, indicatorName string) error {
+	// Start from scratch so that values of a previous response do not accumulate
+	i.Reset()
 
 	var raw map[string]interface{}
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -52,21 +110,26 @@ func UnmarshalIndicatorJSON(i *IndicatorResponse, data []byte, indicatorName str
 	if ok {
 		i.MetaData = extractMetaData(metaData)
 	}
+	i.Function = indicatorName
 
 	// Construct the expected key name
 	expectedKey := "Technical Analysis: " + indicatorName
 
-	// Extracting the indicator values
+	// Extracting the indicator values; entries that cannot be parsed are skipped and reported
+	var errs ParseErrors
 	if tsData, exists := raw[expectedKey].(map[string]interface{}); exists {
+	entries:
 		for k, v := range tsData {
-			timestamp, err := time.Parse("2006-01-02 15:04", k)
+			timestamp, err := parseIndicatorTime(k)
 			if err != nil {
-				return err
+				errs = append(errs, err)
+				continue
 			}
 
 			indicatorData, ok := v.(map[string]interface{})
 			if !ok {
-				return fmt.Errorf("expected map for each timestamp data")
+				errs = append(errs, fmt.Errorf("expected map for the data of %s", k))
+				continue
 			}
 
 			// New changes to extract multiple values
@@ -76,7 +139,8 @@ func UnmarshalIndicatorJSON(i *IndicatorResponse, data []byte, indicatorName str
 				if value, ok := rawValue.(string); ok {
 					floatValue, err := strconv.ParseFloat(value, 64)
 					if err != nil {
-						return err
+						errs = append(errs, fmt.Errorf("error parsing '%s' for %s: %v", name, k, err))
+						continue entries
 					}
 					valueMap[name] = floatValue
 				}
@@ -89,7 +153,55 @@ func UnmarshalIndicatorJSON(i *IndicatorResponse, data []byte, indicatorName str
 			}
 		}
 
-	// Sorting based on timestamps
+	// Sorting based on timestamps, after dropping repeated ones
+	i.IndicatorValues = dedupeIndicatorValues(i.IndicatorValues)
+	sort.SliceStable(i.IndicatorValues, func(a, b int) bool {
+		return i.IndicatorValues[a].Timestamp.Before(i.IndicatorValues[b].Timestamp)
+	})
+
+	return errs.err()
+}
+
+// ParseIndicatorCSV parses the CSV body returned by an indicator with datatype=csv, e.g.
+// "time,RSI" followed by one row per timestamp. The CSV carries no metadata, so only Function
+// and the values are set. Timestamps are either datetimes or, for daily and longer intervals,
+// dates.
+func ParseIndicatorCSV(i *IndicatorResponse, data []byte, indicatorName string) error {
+	i.Reset()
+	i.Function = indicatorName
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("%w: empty indicator CSV", ErrNoData)
+	}
+
+	header := records[0]
+	for _, record := range records[1:] {
+		timestamp, err := parseIndicatorTime(record[0])
+		if err != nil {
+			return err
+		}
+
+		valueMap := make(map[string]float64, len(header)-1)
+		for col := 1; col < len(header) && col < len(record); col++ {
+			value, err := strconv.ParseFloat(record[col], 64)
+			if err != nil {
+				return fmt.Errorf("error parsing '%s' for %s: %v", header[col], record[0], err)
+			}
+			valueMap[header[col]] = value
+		}
+
+		i.IndicatorValues = append(i.IndicatorValues, IndicatorValue{
+			Timestamp: timestamp,
+			Values:    valueMap,
+		})
+	}
+
+	// Sorting based on timestamps, after dropping repeated ones
+	i.IndicatorValues = dedupeIndicatorValues(i.IndicatorValues)
 	sort.SliceStable(i.IndicatorValues, func(a, b int) bool {
 		return i.IndicatorValues[a].Timestamp.Before(i.IndicatorValues[b].Timestamp)
 	})
@@ -97,6 +209,32 @@ func UnmarshalIndicatorJSON(i *IndicatorResponse, data []byte, indicatorName str
 	return nil
 }
 
+// parseIndicatorTime parses the timestamp of an indicator value, in JSON or CSV: a datetime
+// for intraday intervals, or a date for daily and longer ones.
+func parseIndicatorTime(s string) (time.Time, error) {
+	if timestamp, err := time.Parse("2006-01-02 15:04", s); err == nil {
+		return timestamp, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// dedupeIndicatorValues keeps one value per timestamp, the last one, as the API occasionally
+// repeats intraday timestamps around daylight-saving transitions. Repeated keys of a JSON
+// object are already collapsed the same way by encoding/json; repeated CSV rows are not.
+func dedupeIndicatorValues(values []IndicatorValue) []IndicatorValue {
+	index := make(map[time.Time]int, len(values))
+	deduped := values[:0]
+	for _, v := range values {
+		if k, ok := index[v.Timestamp]; ok {
+			deduped[k] = v
+			continue
+		}
+		index[v.Timestamp] = len(deduped)
+		deduped = append(deduped, v)
+	}
+	return deduped
+}
+
 func extractMetaData(rawData map[string]interface{}) TimeSeriesMetaData {
 	var metaData TimeSeriesMetaData
 
@@ -121,9 +259,111 @@ func extractMetaData(rawData map[string]interface{}) TimeSeriesMetaData {
 	return metaData
 }
 
+// OutputNames returns the names of all outputs present in any of the values, sorted
+// alphabetically, e.g. ["Real Lower Band", "Real Middle Band", "Real Upper Band"] for BBANDS.
+func (i IndicatorResponse) OutputNames() []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, v := range i.IndicatorValues {
+		for name := range v.Values {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Series extracts the named output, e.g. "RSI" or "Real Middle Band" for BBANDS, as a slice
+// aligned to the timestamps of the response. Timestamps without that output are skipped.
+// It returns false if no value carries the name.
+func (i IndicatorResponse) Series(name string) ([]IndicatorPoint, bool) {
+	points := make([]IndicatorPoint, 0, len(i.IndicatorValues))
+	for _, v := range i.IndicatorValues {
+		if value, ok := v.Values[name]; ok {
+			points = append(points, IndicatorPoint{Time: v.Timestamp, Value: value})
+		}
+	}
+
+	if len(points) == 0 {
+		return nil, false
+	}
+	return points, true
+}
+
+// Records returns the IndicatorResponse as CSV records in the layout of the API's CSV: a
+// "time" column followed by one column per output, as listed by OutputNames. Outputs missing
+// at a timestamp are left empty. Times are dates for daily and longer intervals.
+func (i IndicatorResponse) Records() [][]string {
+	names := i.OutputNames()
+	layout := i.timeLayout()
+	records := make([][]string, 0, len(i.IndicatorValues)+1)
+	records = append(records, append([]string{"time"}, names...))
+	for _, v := range i.IndicatorValues {
+		record := make([]string, 0, len(names)+1)
+		record = append(record, v.Timestamp.Format(layout))
+		for _, name := range names {
+			value, ok := v.Values[name]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// timeLayout is the layout of the API's timestamps for the interval of the response: a date
+// for daily, weekly and monthly values, and a datetime to the minute otherwise.
+func (i IndicatorResponse) timeLayout() string {
+	switch Interval(i.MetaData.Interval) {
+	case IntervalDaily, IntervalWeekly, IntervalMonthly:
+		return "2006-01-02"
+	}
+	return "2006-01-02 15:04"
+}
+
+// MarshalJSON emits the IndicatorResponse in the layout read by UnmarshalIndicatorJSON
+// so that it can be unmarshaled back into an equivalent struct.
+func (i IndicatorResponse) MarshalJSON() ([]byte, error) {
+	metaData := map[string]interface{}{
+		"1: Symbol":         i.MetaData.Symbol,
+		"2: Indicator":      i.MetaData.Information,
+		"3: Last Refreshed": i.MetaData.LastRefreshed,
+		"4: Interval":       i.MetaData.Interval,
+		"7: Time Zone":      i.MetaData.TimeZone,
+	}
+	if i.MetaData.TimePeriod != 0 {
+		metaData["5: Time Period"] = i.MetaData.TimePeriod
+	}
+	if i.MetaData.SeriesType != "" {
+		metaData["6: Series Type"] = i.MetaData.SeriesType
+	}
+
+	layout := i.timeLayout()
+	values := make(map[string]map[string]string, len(i.IndicatorValues))
+	for _, v := range i.IndicatorValues {
+		row := make(map[string]string, len(v.Values))
+		for name, value := range v.Values {
+			row[name] = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+		values[v.Timestamp.Format(layout)] = row
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"Meta Data":                        metaData,
+		"Technical Analysis: " + i.Function: values,
+	})
+}
 
 func (i IndicatorResponse) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// Print metadata
 	sb.WriteString(i.MetaData.Information + "\n")
@@ -134,33 +374,23 @@ func (i IndicatorResponse) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", i.MetaData.TimeZone))
 	sb.WriteString("\n")
 
-	// Define headers dynamically
-	headers := []string{"Time"}
-	if len(i.IndicatorValues) > 0 {
-		for k := range i.IndicatorValues[0].Values {
-			headers = append(headers, k)
-		}
-	}
-
-	// Print headers
-	sb.WriteString(fmt.Sprintf("%-24s", headers[0]))  // Set width for "Time"
-	for _, header := range headers[1:] {
-		sb.WriteString(fmt.Sprintf("%-15s", header))
-	}
-	sb.WriteString("\n")
-	sb.WriteString(fmt.Sprintf("%-24s", strings.Repeat("=", 24)))  // Set width for "Time"
-	sb.WriteString(strings.Repeat("=", 15*(len(headers)-1)))
-	sb.WriteString("\n")
+	// Define headers from every output name, in a stable order
+	headers := append([]string{"Time"}, i.OutputNames()...)
 
-	// Loop through the Indicator slice
+	rows := make([][]string, 0, len(i.IndicatorValues))
 	for _, v := range i.IndicatorValues {
-		timeStr := v.Timestamp.Format("2006-01-02 15:04:05")
-		sb.WriteString(fmt.Sprintf("%-24s", timeStr))  // Set width for "Time"
-		for _, header := range headers[1:] {  // Skip "Time"
-			sb.WriteString(fmt.Sprintf("%15.2f", v.Values[header]))
-	}
-		sb.WriteString("\n")
+		row := []string{v.Timestamp.Format("2006-01-02 15:04:05")}
+		for _, header := range headers[1:] { // Skip "Time"
+			value, ok := v.Values[header]
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, fmt.Sprintf("%.*f", prec, value))
+		}
+		rows = append(rows, row)
 	}
+	writeTable(&sb, headers, rows)
 
 	return sb.String()
 }
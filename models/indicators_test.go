@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUnmarshalIndicatorJSONDailyDates(t *testing.T) {
+	body := []byte(`{"Meta Data": {"1: Symbol": "IBM", "4: Interval": "daily"}, "Technical Analysis: SMA": {
+		"2023-09-08": {"SMA": "147.1"}, "2023-09-07": {"SMA": "146.9"}}}`)
+
+	var sma IndicatorResponse
+	if err := UnmarshalIndicatorJSON(&sma, body, "SMA"); err != nil {
+		t.Fatal(err)
+	}
+	checkIndicator(t, sma, "SMA", []float64{146.9, 147.1})
+	if got := sma.IndicatorValues[1].Timestamp; !got.Equal(date(2023, 9, 8)) {
+		t.Errorf("timestamp = %v, want 2023-09-08", got)
+	}
+}
+
+func TestParseIndicatorCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []float64
+	}{
+		{"intraday", "time,RSI\n2023-09-08 19:55,55.5\n2023-09-08 19:50,57.91\n", []float64{57.91, 55.5}},
+		{"daily", "time,RSI\n2023-09-08,60.1\n2023-09-07,58.2\n", []float64{58.2, 60.1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rsi IndicatorResponse
+			if err := ParseIndicatorCSV(&rsi, []byte(tt.body), "RSI"); err != nil {
+				t.Fatal(err)
+			}
+			checkIndicator(t, rsi, "RSI", tt.want)
+		})
+	}
+}
+
+func TestParseIndicatorCSVMultipleOutputs(t *testing.T) {
+	body := "time,Real Lower Band,Real Middle Band,Real Upper Band\n2023-09-08 19:55,146.1,147.2,148.3\n"
+
+	var bbands IndicatorResponse
+	if err := ParseIndicatorCSV(&bbands, []byte(body), "BBANDS"); err != nil {
+		t.Fatal(err)
+	}
+	if len(bbands.IndicatorValues) != 1 {
+		t.Fatalf("got %d values, want 1", len(bbands.IndicatorValues))
+	}
+	values := bbands.IndicatorValues[0].Values
+	if values["Real Lower Band"] != 146.1 || values["Real Middle Band"] != 147.2 || values["Real Upper Band"] != 148.3 {
+		t.Errorf("unexpected values %v", values)
+	}
+}
+
+func TestParseIndicatorCSVErrors(t *testing.T) {
+	var rsi IndicatorResponse
+	if err := ParseIndicatorCSV(&rsi, nil, "RSI"); !errors.Is(err, ErrNoData) {
+		t.Errorf("empty body: err = %v, want ErrNoData", err)
+	}
+	if err := ParseIndicatorCSV(&rsi, []byte("time,RSI\nyesterday,50\n"), "RSI"); err == nil {
+		t.Error("bad timestamp: expected an error")
+	}
+	if err := ParseIndicatorCSV(&rsi, []byte("time,RSI\n2023-09-08,n/a\n"), "RSI"); err == nil {
+		t.Error("bad value: expected an error")
+	}
+}
+
+func TestIndicatorDuplicateTimestamps(t *testing.T) {
+	// The 19:50 value is repeated with a revised RSI; the last one wins in both formats
+	want := []float64{55.0874, 57.91, 55.2311}
+
+	t.Run("json", func(t *testing.T) {
+		var rsi IndicatorResponse
+		if err := UnmarshalIndicatorJSON(&rsi, readFixture(t, "rsi_duplicate.json"), "RSI"); err != nil {
+			t.Fatal(err)
+		}
+		checkIndicator(t, rsi, "RSI", want)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		body := "time,RSI\n2023-09-08 19:55,55.2311\n2023-09-08 19:50,57.9036\n2023-09-08 19:50,57.9100\n2023-09-08 19:45,55.0874\n"
+		var rsi IndicatorResponse
+		if err := ParseIndicatorCSV(&rsi, []byte(body), "RSI"); err != nil {
+			t.Fatal(err)
+		}
+		checkIndicator(t, rsi, "RSI", want)
+	})
+}
+
+func TestIndicatorRecordsLayout(t *testing.T) {
+	tests := []struct {
+		interval string
+		want     string
+	}{
+		{"5min", "2023-09-08 19:55"},
+		{"60min", "2023-09-08 19:55"},
+		{"daily", "2023-09-08"},
+		{"weekly", "2023-09-08"},
+		{"monthly", "2023-09-08"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			rsi := IndicatorResponse{
+				MetaData: TimeSeriesMetaData{Interval: tt.interval},
+				IndicatorValues: []IndicatorValue{
+					{Timestamp: time.Date(2023, 9, 8, 19, 55, 0, 0, time.UTC), Values: map[string]float64{"RSI": 55.5}},
+				},
+			}
+			records := rsi.Records()
+			if len(records) != 2 || records[1][0] != tt.want {
+				t.Fatalf("records = %v, want time %q", records, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndicatorMarshalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		fn   string
+	}{
+		{"rsi fixture", readFixture(t, "rsi.json"), "RSI"},
+		{"bbands fixture", readFixture(t, "bbands.json"), "BBANDS"},
+		{"daily", []byte(`{"Meta Data": {"1: Symbol": "IBM", "4: Interval": "daily", "5: Time Period": 20}, "Technical Analysis: SMA": {
+			"2023-09-08": {"SMA": "147.1"}, "2023-09-07": {"SMA": "146.9"}}}`), "SMA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unmarshal := func(i *IndicatorResponse, data []byte) error {
+				return UnmarshalIndicatorJSON(i, data, tt.fn)
+			}
+			first, second := roundTrip(t, tt.data, unmarshal)
+			if len(first.IndicatorValues) == 0 {
+				t.Fatal("no values decoded before marshaling")
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("round trip changed the value:\n got %+v\nwant %+v", second, first)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+//go:build go1.23
+
+/*
+// Package models provides types and functions for working with Alpha Vantage time series data.
+//
+// This file contains range-over-func iterators over the bars of the series types. It is only
+// built with Go 1.23 or newer, where the iter package is available.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import "iter"
+
+// All yields the index and bar of every bar of the TimeSeriesIntraday, oldest first.
+func (t TimeSeriesIntraday) All() iter.Seq2[int, OHLCV] {
+	return allBars(t.TimeSeries)
+}
+
+// Reversed yields the index and bar of every bar of the TimeSeriesIntraday, newest first.
+func (t TimeSeriesIntraday) Reversed() iter.Seq2[int, OHLCV] {
+	return reversedBars(t.TimeSeries)
+}
+
+// All yields the index and bar of every bar of the TimeSeriesDaily, oldest first.
+func (t TimeSeriesDaily) All() iter.Seq2[int, OHLCV] {
+	return allBars(t.TimeSeries)
+}
+
+// Reversed yields the index and bar of every bar of the TimeSeriesDaily, newest first.
+func (t TimeSeriesDaily) Reversed() iter.Seq2[int, OHLCV] {
+	return reversedBars(t.TimeSeries)
+}
+
+// All yields the index and bar of every bar of the TimeSeriesDailyAdjusted, oldest first.
+func (t TimeSeriesDailyAdjusted) All() iter.Seq2[int, AdjustedOHLCV] {
+	return allBars(t.TimeSeries)
+}
+
+// Reversed yields the index and bar of every bar of the TimeSeriesDailyAdjusted, newest first.
+func (t TimeSeriesDailyAdjusted) Reversed() iter.Seq2[int, AdjustedOHLCV] {
+	return reversedBars(t.TimeSeries)
+}
+
+// All yields the index and bar of every bar of the TimeSeriesWeekly, oldest first.
+func (t TimeSeriesWeekly) All() iter.Seq2[int, OHLCV] {
+	return allBars(t.TimeSeries)
+}
+
+// Reversed yields the index and bar of every bar of the TimeSeriesWeekly, newest first.
+func (t TimeSeriesWeekly) Reversed() iter.Seq2[int, OHLCV] {
+	return reversedBars(t.TimeSeries)
+}
+
+// All yields the index and bar of every bar of the TimeSeriesWeeklyAdjusted, oldest first.
+func (t TimeSeriesWeeklyAdjusted) All() iter.Seq2[int, AdjustedOHLCV] {
+	return allBars(t.TimeSeries)
+}
+
+// Reversed yields the index and bar of every bar of the TimeSeriesWeeklyAdjusted, newest first.
+func (t TimeSeriesWeeklyAdjusted) Reversed() iter.Seq2[int, AdjustedOHLCV] {
+	return reversedBars(t.TimeSeries)
+}
+
+// All yields the index and bar of every bar of the TimeSeriesMonthly, oldest first.
+func (t TimeSeriesMonthly) All() iter.Seq2[int, OHLCV] {
+	return allBars(t.TimeSeries)
+}
+
+// Reversed yields the index and bar of every bar of the TimeSeriesMonthly, newest first.
+func (t TimeSeriesMonthly) Reversed() iter.Seq2[int, OHLCV] {
+	return reversedBars(t.TimeSeries)
+}
+
+// All yields the index and bar of every bar of the TimeSeriesMonthlyAdjusted, oldest first.
+func (t TimeSeriesMonthlyAdjusted) All() iter.Seq2[int, AdjustedOHLCV] {
+	return allBars(t.TimeSeries)
+}
+
+// Reversed yields the index and bar of every bar of the TimeSeriesMonthlyAdjusted, newest first.
+func (t TimeSeriesMonthlyAdjusted) Reversed() iter.Seq2[int, AdjustedOHLCV] {
+	return reversedBars(t.TimeSeries)
+}
+
+// All yields the index and bar of every bar of the CryptoSeriesResponse, oldest first.
+func (c CryptoSeriesResponse) All() iter.Seq2[int, CryptoTimeSeriesData] {
+	return allBars(c.TimeSeries)
+}
+
+// Reversed yields the index and bar of every bar of the CryptoSeriesResponse, newest first.
+func (c CryptoSeriesResponse) Reversed() iter.Seq2[int, CryptoTimeSeriesData] {
+	return reversedBars(c.TimeSeries)
+}
+
+// allBars yields the bars in slice order along with their index.
+func allBars[T any](bars []T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, bar := range bars {
+			if !yield(i, bar) {
+				return
+			}
+		}
+	}
+}
+
+// reversedBars yields the bars in reverse slice order along with their index in the slice,
+// so the first pair of a non-empty series is (len-1, last bar).
+func reversedBars[T any](bars []T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := len(bars) - 1; i >= 0; i-- {
+			if !yield(i, bars[i]) {
+				return
+			}
+		}
+	}
+}
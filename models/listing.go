@@ -0,0 +1,155 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage listing data.
+//
+// This file contains types and functions representing the interactions and responses 
+// for the listing status endpoint provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ListingStatus represents a single row of the LISTING_STATUS response.
+type ListingStatus struct {
+	Symbol        string
+	Name          string
+	Exchange      string
+	AssetType     string
+	IPODate       time.Time
+	DelistingDate *time.Time // nil while the symbol is still listed
+	Status        string
+}
+
+// ParseListingStatusCSV parses the CSV body returned by the LISTING_STATUS endpoint.
+func ParseListingStatusCSV(data []byte) ([]ListingStatus, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty listing status response")
+	}
+
+	// Map the header names to their column positions
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	for _, name := range []string{"symbol", "name", "exchange", "assetType", "ipoDate", "delistingDate", "status"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("listing status response is missing column %q", name)
+		}
+	}
+
+	listings := make([]ListingStatus, 0, len(records)-1)
+	for _, record := range records[1:] {
+		listing := ListingStatus{
+			Symbol:    record[columns["symbol"]],
+			Name:      record[columns["name"]],
+			Exchange:  record[columns["exchange"]],
+			AssetType: record[columns["assetType"]],
+			Status:    record[columns["status"]],
+		}
+
+		ipoDate, err := time.Parse("2006-01-02", record[columns["ipoDate"]])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'ipoDate' for %s: %v", listing.Symbol, err)
+		}
+		listing.IPODate = ipoDate
+
+		if delisted := record[columns["delistingDate"]]; delisted != "" && delisted != "null" {
+			delistingDate, err := time.Parse("2006-01-02", delisted)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing 'delistingDate' for %s: %v", listing.Symbol, err)
+			}
+			listing.DelistingDate = &delistingDate
+		}
+
+		listings = append(listings, listing)
+	}
+
+	return listings, nil
+}
+
+// SymbolDirectory is an in-memory index over a LISTING_STATUS snapshot, so that symbols can be
+// looked up without further requests. It is not modified after creation and is safe for
+// concurrent use.
+type SymbolDirectory struct {
+	listings   []ListingStatus
+	bySymbol   map[string]int
+	byExchange map[string][]int
+}
+
+// NewSymbolDirectory indexes listings, e.g. as returned by ParseListingStatusCSV. When a symbol
+// appears more than once, as it can in a delisted snapshot, Find returns its first listing.
+func NewSymbolDirectory(listings []ListingStatus) *SymbolDirectory {
+	d := &SymbolDirectory{
+		listings:   append([]ListingStatus(nil), listings...),
+		bySymbol:   make(map[string]int, len(listings)),
+		byExchange: make(map[string][]int),
+	}
+	for i, listing := range d.listings {
+		symbol := NormalizeSymbol(listing.Symbol)
+		if _, ok := d.bySymbol[symbol]; !ok {
+			d.bySymbol[symbol] = i
+		}
+		exchange := strings.ToUpper(listing.Exchange)
+		d.byExchange[exchange] = append(d.byExchange[exchange], i)
+	}
+	return d
+}
+
+// Len returns the number of listings in the directory.
+func (d *SymbolDirectory) Len() int {
+	return len(d.listings)
+}
+
+// Find returns the listing of symbol, which is normalized like NormalizeSymbol, and whether it
+// is in the directory.
+func (d *SymbolDirectory) Find(symbol string) (ListingStatus, bool) {
+	i, ok := d.bySymbol[NormalizeSymbol(symbol)]
+	if !ok {
+		return ListingStatus{}, false
+	}
+	return d.listings[i], true
+}
+
+// ByExchange returns the listings of exchange, e.g. "NYSE" or "NASDAQ", matched
+// case-insensitively, in directory order.
+func (d *SymbolDirectory) ByExchange(exchange string) []ListingStatus {
+	indexes := d.byExchange[strings.ToUpper(strings.TrimSpace(exchange))]
+	listings := make([]ListingStatus, len(indexes))
+	for i, index := range indexes {
+		listings[i] = d.listings[index]
+	}
+	return listings
+}
+
+// Search returns the listings whose symbol or name contains substring, ignoring case, in
+// directory order. An empty substring matches nothing.
+func (d *SymbolDirectory) Search(substring string) []ListingStatus {
+	substring = strings.ToLower(strings.TrimSpace(substring))
+	if substring == "" {
+		return nil
+	}
+
+	var matches []ListingStatus
+	for _, listing := range d.listings {
+		if strings.Contains(strings.ToLower(listing.Symbol), substring) ||
+			strings.Contains(strings.ToLower(listing.Name), substring) {
+			matches = append(matches, listing)
+		}
+	}
+	return matches
+}
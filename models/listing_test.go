@@ -0,0 +1,82 @@
+package models
+
+import "testing"
+
+// listingSymbols returns the symbols of listings, in order.
+func listingSymbols(listings []ListingStatus) []string {
+	symbols := make([]string, len(listings))
+	for i, listing := range listings {
+		symbols[i] = listing.Symbol
+	}
+	return symbols
+}
+
+func TestParseListingStatusCSV(t *testing.T) {
+	listings, err := ParseListingStatusCSV(readFixture(t, "listing_status.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listings) != 5 {
+		t.Fatalf("got %d listings, want 5", len(listings))
+	}
+	ibm := listings[2]
+	if ibm.Symbol != "IBM" || ibm.Exchange != "NYSE" || !ibm.IPODate.Equal(date(1962, 1, 2)) || ibm.DelistingDate != nil {
+		t.Errorf("unexpected listing %+v", ibm)
+	}
+}
+
+func TestSymbolDirectory(t *testing.T) {
+	listings, err := ParseListingStatusCSV(readFixture(t, "listing_status.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewSymbolDirectory(listings)
+
+	if d.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", d.Len())
+	}
+
+	if listing, ok := d.Find(" msft "); !ok || listing.Name != "Microsoft Corporation" {
+		t.Errorf("Find(msft) = %+v, %v", listing, ok)
+	}
+	if _, ok := d.Find("GOOG"); ok {
+		t.Error("Find(GOOG) found a listing missing from the snapshot")
+	}
+
+	tests := []struct {
+		name string
+		got  []ListingStatus
+		want []string
+	}{
+		{"by exchange", d.ByExchange("nyse"), []string{"A", "IBM"}},
+		{"by exchange with space", d.ByExchange("NYSE ARCA"), []string{"SPY"}},
+		{"by unknown exchange", d.ByExchange("LSE"), []string{}},
+		{"search symbol", d.Search("ibm"), []string{"IBM"}},
+		{"search name", d.Search("corp"), []string{"IBM", "MSFT"}},
+		{"search empty", d.Search(" "), []string{}},
+	}
+	for _, tt := range tests {
+		got := listingSymbols(tt.got)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSymbolDirectoryDuplicateSymbol(t *testing.T) {
+	delisted := date(2020, 5, 1)
+	d := NewSymbolDirectory([]ListingStatus{
+		{Symbol: "ABC", Name: "First", DelistingDate: &delisted},
+		{Symbol: "ABC", Name: "Second"},
+	})
+	if listing, _ := d.Find("ABC"); listing.Name != "First" {
+		t.Errorf("Find returned %q, want the first listing", listing.Name)
+	}
+}
@@ -0,0 +1,249 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage market data.
+//
+// This file contains types and functions representing the interactions and responses 
+// for market-wide endpoints such as the top gainers and losers provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarketMover represents a single ticker entry of the TOP_GAINERS_LOSERS response.
+type MarketMover struct {
+	Ticker           string
+	Price            float64
+	ChangeAmount     float64
+	ChangePercentage float64 // in percent, e.g. 1.5 for "1.5%"
+	Volume           int64
+}
+
+// MarketMovers represents the response for the TOP_GAINERS_LOSERS endpoint.
+type MarketMovers struct {
+	Metadata           string
+	LastUpdated        time.Time
+	TopGainers         []MarketMover
+	TopLosers          []MarketMover
+	MostActivelyTraded []MarketMover
+}
+
+// UnmarshalJSON is a custom unmarshaler for the MarketMovers struct.
+func (m *MarketMovers) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Metadata           string              `json:"metadata"`
+		LastUpdated        string              `json:"last_updated"`
+		TopGainers         []map[string]string `json:"top_gainers"`
+		TopLosers          []map[string]string `json:"top_losers"`
+		MostActivelyTraded []map[string]string `json:"most_actively_traded"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	m.Metadata = raw.Metadata
+
+	if raw.LastUpdated != "" {
+		lastUpdated, err := parseLastUpdated(raw.LastUpdated)
+		if err != nil {
+			return fmt.Errorf("error parsing 'last_updated': %v", err)
+		}
+		m.LastUpdated = lastUpdated
+	}
+
+	var err error
+	if m.TopGainers, err = parseMarketMovers(raw.TopGainers); err != nil {
+		return err
+	}
+	if m.TopLosers, err = parseMarketMovers(raw.TopLosers); err != nil {
+		return err
+	}
+	if m.MostActivelyTraded, err = parseMarketMovers(raw.MostActivelyTraded); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseLastUpdated parses timestamps such as "2023-09-08 16:15:59 US/Eastern". The
+// trailing zone name is honored when it can be loaded, otherwise UTC is assumed.
+func parseLastUpdated(value string) (time.Time, error) {
+	loc := time.UTC
+	if idx := strings.LastIndex(value, " "); idx > len("2006-01-02") {
+		if zone, err := time.LoadLocation(value[idx+1:]); err == nil {
+			loc = zone
+		}
+		value = value[:idx]
+	}
+	return time.ParseInLocation("2006-01-02 15:04:05", value, loc)
+}
+
+func parseMarketMovers(rawMovers []map[string]string) ([]MarketMover, error) {
+	movers := make([]MarketMover, 0, len(rawMovers))
+	for _, raw := range rawMovers {
+		mover := MarketMover{Ticker: raw["ticker"]}
+
+		price, err := strconv.ParseFloat(raw["price"], 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'price' for %s: %v", mover.Ticker, err)
+		}
+		mover.Price = price
+
+		changeAmount, err := strconv.ParseFloat(raw["change_amount"], 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'change_amount' for %s: %v", mover.Ticker, err)
+		}
+		mover.ChangeAmount = changeAmount
+
+		changePercentage, err := strconv.ParseFloat(strings.TrimSuffix(raw["change_percentage"], "%"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'change_percentage' for %s: %v", mover.Ticker, err)
+		}
+		mover.ChangePercentage = changePercentage
+
+		volume, err := strconv.ParseInt(raw["volume"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'volume' for %s: %v", mover.Ticker, err)
+		}
+		mover.Volume = volume
+
+		movers = append(movers, mover)
+	}
+	return movers, nil
+}
+
+// String representation of the MarketMovers for custom printing.
+func (m MarketMovers) String() string {
+	var sb strings.Builder
+
+	// First, print metadata
+	sb.WriteString(m.Metadata + "\n")
+	sb.WriteString(fmt.Sprintf("Last Updated: %s\n", m.LastUpdated.Format("2006-01-02 15:04:05 MST")))
+
+	writeMarketMoversTable(&sb, "Top Gainers", m.TopGainers)
+	writeMarketMoversTable(&sb, "Top Losers", m.TopLosers)
+	writeMarketMoversTable(&sb, "Most Actively Traded", m.MostActivelyTraded)
+
+	return sb.String()
+}
+
+func writeMarketMoversTable(sb *strings.Builder, title string, movers []MarketMover) {
+	sb.WriteString("\n" + title + "\n")
+
+	// Define headers for the dataframe-style table
+	headers := []string{"Ticker", "Price", "Change", "Change %", "Volume"}
+	sb.WriteString(fmt.Sprintf("%-10s", headers[0]))
+	for _, header := range headers[1:] {
+		sb.WriteString(fmt.Sprintf("%-15s", header))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("=", 10+(len(headers)-1)*15))
+	sb.WriteString("\n")
+
+	prec := precision(4)
+	for _, v := range movers {
+		sb.WriteString(fmt.Sprintf("%-10s%-15.*f%-15.*f%-15s%-15d\n", v.Ticker, prec, v.Price, prec, v.ChangeAmount, fmt.Sprintf("%.2f%%", v.ChangePercentage), v.Volume))
+	}
+}
+
+// MarketSession represents a single entry of the MARKET_STATUS response.
+type MarketSession struct {
+	MarketType       string
+	Region           string
+	PrimaryExchanges string
+	LocalOpen        time.Time // time of day in the market's local zone, on January 1 of year 0
+	LocalClose       time.Time // time of day in the market's local zone, on January 1 of year 0
+	CurrentStatus    string    // "open" or "closed"
+	Notes            string
+}
+
+// MarketStatus represents the response for the MARKET_STATUS endpoint.
+type MarketStatus struct {
+	Endpoint string
+	Markets  []MarketSession
+}
+
+// UnmarshalJSON is a custom unmarshaler for the MarketStatus struct.
+func (m *MarketStatus) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Endpoint string `json:"endpoint"`
+		Markets  []struct {
+			MarketType       string `json:"market_type"`
+			Region           string `json:"region"`
+			PrimaryExchanges string `json:"primary_exchanges"`
+			LocalOpen        string `json:"local_open"`
+			LocalClose       string `json:"local_close"`
+			CurrentStatus    string `json:"current_status"`
+			Notes            string `json:"notes"`
+		} `json:"markets"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	m.Endpoint = raw.Endpoint
+	m.Markets = make([]MarketSession, 0, len(raw.Markets))
+	for _, market := range raw.Markets {
+		localOpen, err := time.Parse("15:04", market.LocalOpen)
+		if err != nil {
+			return fmt.Errorf("error parsing 'local_open' for %s: %v", market.Region, err)
+		}
+		localClose, err := time.Parse("15:04", market.LocalClose)
+		if err != nil {
+			return fmt.Errorf("error parsing 'local_close' for %s: %v", market.Region, err)
+		}
+
+		m.Markets = append(m.Markets, MarketSession{
+			MarketType:       market.MarketType,
+			Region:           market.Region,
+			PrimaryExchanges: market.PrimaryExchanges,
+			LocalOpen:        localOpen,
+			LocalClose:       localClose,
+			CurrentStatus:    market.CurrentStatus,
+			Notes:            market.Notes,
+		})
+	}
+
+	return nil
+}
+
+// IsOpen reports whether any market in region, e.g. "United States", is currently open.
+// The region is matched case-insensitively.
+func (m MarketStatus) IsOpen(region string) bool {
+	for _, market := range m.Markets {
+		if strings.EqualFold(market.Region, region) && strings.EqualFold(market.CurrentStatus, "open") {
+			return true
+		}
+	}
+	return false
+}
+
+// String representation of the MarketStatus for custom printing.
+func (m MarketStatus) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(m.Endpoint + "\n\n")
+
+	headers := []string{"Market", "Region", "Open", "Close", "Status"}
+	rows := make([][]string, 0, len(m.Markets))
+	for _, market := range m.Markets {
+		rows = append(rows, []string{
+			market.MarketType,
+			market.Region,
+			market.LocalOpen.Format("15:04"),
+			market.LocalClose.Format("15:04"),
+			market.CurrentStatus,
+		})
+	}
+	writeTable(&sb, headers, rows)
+
+	return sb.String()
+}
@@ -0,0 +1,183 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage news data.
+//
+// This file contains types and functions representing the interactions and responses
+// for the news and sentiment endpoint provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// NewsTimeLayout is the YYYYMMDDTHHMM layout of the time_from and time_to parameters.
+// Publication times in the response carry seconds as well.
+const NewsTimeLayout = "20060102T1504"
+
+// NewsSort orders the articles of a NEWS_SENTIMENT response.
+type NewsSort string
+
+// Sort orders supported by the NEWS_SENTIMENT endpoint.
+const (
+	NewsSortLatest    NewsSort = "LATEST"
+	NewsSortEarliest  NewsSort = "EARLIEST"
+	NewsSortRelevance NewsSort = "RELEVANCE"
+)
+
+// MaxNewsLimit is the largest number of articles NEWS_SENTIMENT returns per request.
+const MaxNewsLimit = 1000
+
+// NewsParams represents the parameters for querying NEWS_SENTIMENT. Zero fields are omitted,
+// leaving the API defaults: the latest 50 articles on any ticker and topic. Times are sent in UTC.
+type NewsParams struct {
+	Tickers  []string // e.g. ["AAPL", "CRYPTO:BTC", "FOREX:USD"], joined by commas
+	Topics   []string // e.g. ["technology", "ipo"], joined by commas
+	TimeFrom time.Time
+	TimeTo   time.Time
+	Sort     NewsSort
+	Limit    int // 1 to MaxNewsLimit
+}
+
+// Validate checks the parameters the API would otherwise reject or silently clamp.
+func (p NewsParams) Validate() error {
+	if p.Limit != 0 && (p.Limit < 1 || p.Limit > MaxNewsLimit) {
+		return fmt.Errorf("invalid limit %d: must be between 1 and %d", p.Limit, MaxNewsLimit)
+	}
+	return nil
+}
+
+// NewsTopic is a topic an article is classified under, with its relevance from 0 to 1.
+type NewsTopic struct {
+	Topic     string
+	Relevance float64
+}
+
+// TickerSentiment is the sentiment of an article towards one of the tickers it mentions.
+type TickerSentiment struct {
+	Ticker         string
+	Relevance      float64
+	SentimentScore float64
+	SentimentLabel string
+}
+
+// NewsArticle represents one article of the NEWS_SENTIMENT feed.
+type NewsArticle struct {
+	Title                 string
+	URL                   string
+	TimePublished         time.Time
+	Authors               []string
+	Summary               string
+	Source                string
+	SourceDomain          string
+	Topics                []NewsTopic
+	OverallSentimentScore float64
+	OverallSentimentLabel string
+	TickerSentiment       []TickerSentiment
+}
+
+// NewsSentiment represents the response for the NEWS_SENTIMENT endpoint.
+type NewsSentiment struct {
+	Items                    int
+	SentimentScoreDefinition string
+	RelevanceScoreDefinition string
+	Feed                     []NewsArticle
+}
+
+// UnmarshalJSON is a custom unmarshaler for the NewsSentiment struct.
+func (n *NewsSentiment) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Items                    string `json:"items"`
+		SentimentScoreDefinition string `json:"sentiment_score_definition"`
+		RelevanceScoreDefinition string `json:"relevance_score_definition"`
+		Feed                     []struct {
+			Title                 string   `json:"title"`
+			URL                   string   `json:"url"`
+			TimePublished         string   `json:"time_published"`
+			Authors               []string `json:"authors"`
+			Summary               string   `json:"summary"`
+			Source                string   `json:"source"`
+			SourceDomain          string   `json:"source_domain"`
+			OverallSentimentScore float64  `json:"overall_sentiment_score"`
+			OverallSentimentLabel string   `json:"overall_sentiment_label"`
+			Topics                []struct {
+				Topic          string `json:"topic"`
+				RelevanceScore string `json:"relevance_score"`
+			} `json:"topics"`
+			TickerSentiment []struct {
+				Ticker               string `json:"ticker"`
+				RelevanceScore       string `json:"relevance_score"`
+				TickerSentimentScore string `json:"ticker_sentiment_score"`
+				TickerSentimentLabel string `json:"ticker_sentiment_label"`
+			} `json:"ticker_sentiment"`
+		} `json:"feed"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	n.SentimentScoreDefinition = raw.SentimentScoreDefinition
+	n.RelevanceScoreDefinition = raw.RelevanceScoreDefinition
+	if raw.Items != "" {
+		items, err := strconv.Atoi(raw.Items)
+		if err != nil {
+			return fmt.Errorf("error parsing 'items': %v", err)
+		}
+		n.Items = items
+	}
+
+	n.Feed = make([]NewsArticle, 0, len(raw.Feed))
+	for _, rawArticle := range raw.Feed {
+		article := NewsArticle{
+			Title:                 rawArticle.Title,
+			URL:                   rawArticle.URL,
+			Authors:               rawArticle.Authors,
+			Summary:               rawArticle.Summary,
+			Source:                rawArticle.Source,
+			SourceDomain:          rawArticle.SourceDomain,
+			OverallSentimentScore: rawArticle.OverallSentimentScore,
+			OverallSentimentLabel: rawArticle.OverallSentimentLabel,
+		}
+
+		published, err := time.Parse("20060102T150405", rawArticle.TimePublished)
+		if err != nil {
+			return fmt.Errorf("error parsing 'time_published' for %q: %v", rawArticle.Title, err)
+		}
+		article.TimePublished = published
+
+		for _, topic := range rawArticle.Topics {
+			relevance, err := strconv.ParseFloat(topic.RelevanceScore, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing 'relevance_score' of topic %s: %v", topic.Topic, err)
+			}
+			article.Topics = append(article.Topics, NewsTopic{Topic: topic.Topic, Relevance: relevance})
+		}
+
+		for _, ts := range rawArticle.TickerSentiment {
+			relevance, err := strconv.ParseFloat(ts.RelevanceScore, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing 'relevance_score' for %s: %v", ts.Ticker, err)
+			}
+			score, err := strconv.ParseFloat(ts.TickerSentimentScore, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing 'ticker_sentiment_score' for %s: %v", ts.Ticker, err)
+			}
+			article.TickerSentiment = append(article.TickerSentiment, TickerSentiment{
+				Ticker:         ts.Ticker,
+				Relevance:      relevance,
+				SentimentScore: score,
+				SentimentLabel: ts.TickerSentimentLabel,
+			})
+		}
+
+		n.Feed = append(n.Feed, article)
+	}
+
+	return nil
+}
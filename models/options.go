@@ -0,0 +1,154 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage options data.
+//
+// This file contains types and functions representing the interactions and responses 
+// for the REALTIME_OPTIONS and HISTORICAL_OPTIONS endpoints provided by the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// OptionContract represents a single contract of an options chain. Fields missing from the
+// response, such as the greeks of a realtime chain requested without them, are left at zero.
+type OptionContract struct {
+	ContractID        string
+	Symbol            string
+	Expiration        time.Time
+	Strike            float64
+	Type              string // "call" or "put"
+	Last              float64
+	Mark              float64
+	Bid               float64
+	BidSize           int64
+	Ask               float64
+	AskSize           int64
+	Volume            int64
+	OpenInterest      int64
+	Date              time.Time // trading day of the quote; zero for realtime chains
+	ImpliedVolatility float64
+	Delta             float64
+	Gamma             float64
+	Theta             float64
+	Vega              float64
+	Rho               float64
+}
+
+// OptionsChain represents the response for the REALTIME_OPTIONS and HISTORICAL_OPTIONS endpoints.
+type OptionsChain struct {
+	Endpoint  string
+	Message   string
+	Contracts []OptionContract // sorted by expiration, then strike
+}
+
+// UnmarshalJSON is a custom unmarshaler for the OptionsChain struct.
+func (o *OptionsChain) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Endpoint string              `json:"endpoint"`
+		Message  string              `json:"message"`
+		Data     []map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	o.Endpoint = raw.Endpoint
+	o.Message = raw.Message
+	o.Contracts = make([]OptionContract, 0, len(raw.Data))
+	for _, rawContract := range raw.Data {
+		contract, err := parseOptionContract(rawContract)
+		if err != nil {
+			return err
+		}
+		o.Contracts = append(o.Contracts, contract)
+	}
+
+	sort.SliceStable(o.Contracts, func(a, b int) bool {
+		if !o.Contracts[a].Expiration.Equal(o.Contracts[b].Expiration) {
+			return o.Contracts[a].Expiration.Before(o.Contracts[b].Expiration)
+		}
+		return o.Contracts[a].Strike < o.Contracts[b].Strike
+	})
+
+	return nil
+}
+
+func parseOptionContract(raw map[string]string) (OptionContract, error) {
+	c := OptionContract{
+		ContractID: raw["contractID"],
+		Symbol:     raw["symbol"],
+		Type:       raw["type"],
+	}
+
+	expiration, err := time.Parse("2006-01-02", raw["expiration"])
+	if err != nil {
+		return c, fmt.Errorf("error parsing 'expiration' for %s: %v", c.ContractID, err)
+	}
+	c.Expiration = expiration
+
+	if date, ok := raw["date"]; ok {
+		if c.Date, err = time.Parse("2006-01-02", date); err != nil {
+			return c, fmt.Errorf("error parsing 'date' for %s: %v", c.ContractID, err)
+		}
+	}
+
+	floatFields := []struct {
+		name string
+		dst  *float64
+	}{
+		{"strike", &c.Strike},
+		{"last", &c.Last},
+		{"mark", &c.Mark},
+		{"bid", &c.Bid},
+		{"ask", &c.Ask},
+		{"implied_volatility", &c.ImpliedVolatility},
+		{"delta", &c.Delta},
+		{"gamma", &c.Gamma},
+		{"theta", &c.Theta},
+		{"vega", &c.Vega},
+		{"rho", &c.Rho},
+	}
+	for _, field := range floatFields {
+		value, ok := raw[field.name]
+		if !ok {
+			continue
+		}
+		parsed, err := parseFinancialFloat(value)
+		if err != nil {
+			return c, fmt.Errorf("error parsing '%s' for %s: %v", field.name, c.ContractID, err)
+		}
+		*field.dst = parsed
+	}
+
+	intFields := []struct {
+		name string
+		dst  *int64
+	}{
+		{"bid_size", &c.BidSize},
+		{"ask_size", &c.AskSize},
+		{"volume", &c.Volume},
+		{"open_interest", &c.OpenInterest},
+	}
+	for _, field := range intFields {
+		value, ok := raw[field.name]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return c, fmt.Errorf("error parsing '%s' for %s: %v", field.name, c.ContractID, err)
+		}
+		*field.dst = parsed
+	}
+
+	return c, nil
+}
@@ -0,0 +1,227 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage request parameters.
+//
+// This file contains the typed values accepted by the parameter structs
+// of the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Interval represents the spacing between two consecutive data points.
+type Interval string
+
+// Intervals supported by the Alpha Vantage API.
+const (
+	Interval1Min    Interval = "1min"
+	Interval5Min    Interval = "5min"
+	Interval15Min   Interval = "15min"
+	Interval30Min   Interval = "30min"
+	Interval60Min   Interval = "60min"
+	IntervalDaily   Interval = "daily"
+	IntervalWeekly  Interval = "weekly"
+	IntervalMonthly Interval = "monthly"
+)
+
+// IsValid reports whether the interval is one of the intervals supported by the API.
+func (i Interval) IsValid() bool {
+	switch i {
+	case Interval1Min, Interval5Min, Interval15Min, Interval30Min, Interval60Min,
+		IntervalDaily, IntervalWeekly, IntervalMonthly:
+		return true
+	}
+	return false
+}
+
+// invalidIntervalError is the error of the Validate methods for an interval IsValid rejects.
+func invalidIntervalError(i Interval) error {
+	return fmt.Errorf("invalid interval %q: must be 1min, 5min, 15min, 30min, 60min, daily, weekly or monthly", i)
+}
+
+// IsIntraday reports whether the interval is one of the intraday intervals, 1min to 60min.
+func (i Interval) IsIntraday() bool {
+	switch i {
+	case Interval1Min, Interval5Min, Interval15Min, Interval30Min, Interval60Min:
+		return true
+	}
+	return false
+}
+
+// SeriesType selects the price an indicator is computed from.
+type SeriesType string
+
+// Series types supported by the Alpha Vantage API.
+const (
+	SeriesTypeClose SeriesType = "close"
+	SeriesTypeOpen  SeriesType = "open"
+	SeriesTypeHigh  SeriesType = "high"
+	SeriesTypeLow   SeriesType = "low"
+)
+
+// IsValid reports whether the series type is one of the types supported by the API.
+func (s SeriesType) IsValid() bool {
+	switch s {
+	case SeriesTypeClose, SeriesTypeOpen, SeriesTypeHigh, SeriesTypeLow:
+		return true
+	}
+	return false
+}
+
+// DataType selects the format of a response. When it is left empty the parameter is omitted
+// and the API responds with JSON.
+type DataType string
+
+// Data types supported by the Alpha Vantage API.
+const (
+	DataTypeJSON DataType = "json"
+	DataTypeCSV  DataType = "csv"
+)
+
+// IsValid reports whether the data type is one of the types supported by the API.
+func (d DataType) IsValid() bool {
+	return d == DataTypeJSON || d == DataTypeCSV
+}
+
+// OutputSize selects how many data points a series endpoint returns. When it is left empty the
+// parameter is omitted and the API returns the compact size.
+type OutputSize string
+
+// Output sizes supported by the Alpha Vantage API.
+const (
+	OutputSizeCompact OutputSize = "compact" // the latest 100 data points
+	OutputSizeFull    OutputSize = "full"    // the full history, or the full month when month is set
+)
+
+// IsValid reports whether the output size is one of the sizes supported by the API.
+func (o OutputSize) IsValid() bool {
+	return o == OutputSizeCompact || o == OutputSizeFull
+}
+
+// NormalizeSymbol trims surrounding whitespace from symbol and upper-cases it, which is the form
+// the API expects, e.g. " brk.a " becomes "BRK.A".
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// ValidateSymbol rejects symbols that cannot be valid: empty ones and ones with whitespace
+// inside. Dots and hyphens, as in "BRK.A", "RDS-A" or "TSCO.LON", are accepted.
+func ValidateSymbol(symbol string) error {
+	if symbol == "" {
+		return fmt.Errorf("empty symbol")
+	}
+	if strings.IndexFunc(symbol, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("invalid symbol %q: contains whitespace", symbol)
+	}
+	return nil
+}
+
+// SymbolWithExchange composes symbol with the exchange suffix exch, as the API expects for
+// symbols outside the US, e.g. ("tsco", "LON") becomes "TSCO.LON". A leading dot on exch is
+// optional. The symbol is returned normalized but otherwise unchanged when exch is empty, when
+// it already carries an exchange suffix, as in "BA.LSE", or when it is prefixed like
+// "CRYPTO:BTC". A single letter after the dot is a share class, so "BRK.B" is still suffixed.
+//
+// The suffixes and the exchanges covered vary by endpoint and change over time; the SYMBOL_SEARCH
+// endpoint shows the form Alpha Vantage uses for a given listing.
+func SymbolWithExchange(symbol, exch string) string {
+	symbol = NormalizeSymbol(symbol)
+	exch = strings.TrimPrefix(NormalizeSymbol(exch), ".")
+	if exch == "" || symbol == "" || strings.Contains(symbol, ":") || hasExchangeSuffix(symbol) {
+		return symbol
+	}
+	return symbol + "." + exch
+}
+
+// hasExchangeSuffix reports whether symbol ends in an exchange suffix, i.e. two or more
+// characters after its last dot.
+func hasExchangeSuffix(symbol string) bool {
+	dot := strings.LastIndex(symbol, ".")
+	return dot >= 0 && len(symbol)-dot-1 >= 2
+}
+
+// MonthsBetween returns the months from the month of from to the month of to, inclusive, as
+// the YYYY-MM strings taken by the month parameter, e.g. ["2023-11", "2023-12", "2024-01"]. A
+// range within one month yields that month alone; a range ending before it starts yields nil.
+func MonthsBetween(from, to time.Time) []string {
+	if to.Before(from) {
+		return nil
+	}
+
+	var months []string
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for ; !month.After(last); month = month.AddDate(0, 1, 0) {
+		months = append(months, month.Format("2006-01"))
+	}
+	return months
+}
+
+// NewIndicatorParams returns the IndicatorParams of the common indicator request: symbol over
+// interval, e.g. "daily", computed over period data points of seriesType, e.g. "close". The
+// function is set by the client method it is passed to; the optional fields can be set on the
+// result.
+func NewIndicatorParams(symbol, interval string, period int, seriesType string) IndicatorParams {
+	return IndicatorParams{
+		Symbol:     symbol,
+		Interval:   Interval(interval),
+		TimePeriod: period,
+		SeriesType: SeriesType(seriesType),
+	}
+}
+
+// TimeSeriesOption sets an optional field of TimeSeriesParams in NewTimeSeriesParams.
+type TimeSeriesOption func(*TimeSeriesParams)
+
+// NewTimeSeriesParams returns the TimeSeriesParams for symbol with the given options applied.
+// The interval, required by the intraday endpoints only, is set with WithInterval.
+func NewTimeSeriesParams(symbol string, opts ...TimeSeriesOption) TimeSeriesParams {
+	params := TimeSeriesParams{Symbol: symbol}
+	for _, opt := range opts {
+		opt(&params)
+	}
+	return params
+}
+
+// WithInterval sets the interval of an intraday request.
+func WithInterval(interval Interval) TimeSeriesOption {
+	return func(p *TimeSeriesParams) {
+		p.Interval = interval
+	}
+}
+
+// WithMonth requests a month of intraday history, formatted as "2009-01".
+func WithMonth(month string) TimeSeriesOption {
+	return func(p *TimeSeriesParams) {
+		p.Month = month
+	}
+}
+
+// WithOutputSize sets the output size of the request.
+func WithOutputSize(size OutputSize) TimeSeriesOption {
+	return func(p *TimeSeriesParams) {
+		p.OutputSize = size
+	}
+}
+
+// WithDataType sets the response format.
+func WithDataType(dataType DataType) TimeSeriesOption {
+	return func(p *TimeSeriesParams) {
+		p.DataType = dataType
+	}
+}
+
+// WithAdjusted sets whether intraday prices are adjusted for splits and dividends.
+func WithAdjusted(adjusted bool) TimeSeriesOption {
+	return func(p *TimeSeriesParams) {
+		p.Adjusted = &adjusted
+	}
+}
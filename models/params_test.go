@@ -0,0 +1,112 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeSymbol(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{" aapl ", "AAPL"},
+		{"brk.a", "BRK.A"},
+		{"RDS-A", "RDS-A"},
+		{"\ttsco.lon\n", "TSCO.LON"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeSymbol(tt.in); got != tt.want {
+			t.Errorf("NormalizeSymbol(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSymbol(t *testing.T) {
+	tests := []struct {
+		symbol  string
+		wantErr bool
+	}{
+		{"AAPL", false},
+		{"BRK.A", false},
+		{"RDS-A", false},
+		{"TSCO.LON", false},
+		{"", true},
+		{"BRK A", true},
+		{"AA\tPL", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateSymbol(tt.symbol); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSymbol(%q) = %v, want error %v", tt.symbol, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMonthsBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{"range", date(2023, 11, 15), date(2024, 1, 10), []string{"2023-11", "2023-12", "2024-01"}},
+		{"same month", date(2024, 2, 1), date(2024, 2, 29), []string{"2024-02"}},
+		{"same day", date(2024, 2, 1), date(2024, 2, 1), []string{"2024-02"}},
+		{"reversed", date(2024, 2, 1), date(2024, 1, 31), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MonthsBetween(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MonthsBetween = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(Interval) error
+	}{
+		{"time series", func(i Interval) error { return TimeSeriesParams{Symbol: "IBM", Interval: i}.Validate() }},
+		{"indicator", func(i Interval) error { return IndicatorParams{Symbol: "IBM", Interval: i}.Validate() }},
+		{"crypto", func(i Interval) error {
+			return CryptoParams{Symbol: "BTC", Market: "USD", Interval: string(i)}.Validate()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, interval := range []Interval{"", Interval5Min, IntervalDaily} {
+				if err := tt.validate(interval); err != nil {
+					t.Errorf("interval %q: %v", interval, err)
+				}
+			}
+			for _, interval := range []Interval{"5mins", "Daily", "2min"} {
+				if err := tt.validate(interval); err == nil {
+					t.Errorf("interval %q: expected an error", interval)
+				}
+			}
+		})
+	}
+}
+
+func TestSymbolWithExchange(t *testing.T) {
+	tests := []struct {
+		name, symbol, exch, want string
+	}{
+		{"bare ticker", "tsco", "LON", "TSCO.LON"},
+		{"dotted exchange", "TSCO", ".lon", "TSCO.LON"},
+		{"already suffixed", "TSCO.LON", "LON", "TSCO.LON"},
+		{"other suffix kept", "BA.LSE", "LON", "BA.LSE"},
+		{"empty exchange", " ibm ", "", "IBM"},
+		{"share class", "BRK.B", "TRT", "BRK.B.TRT"},
+		{"prefixed", "CRYPTO:BTC", "LON", "CRYPTO:BTC"},
+		{"empty symbol", "", "LON", ""},
+	}
+	for _, tt := range tests {
+		if got := SymbolWithExchange(tt.symbol, tt.exch); got != tt.want {
+			t.Errorf("%s: SymbolWithExchange(%q, %q) = %q, want %q", tt.name, tt.symbol, tt.exch, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+// Package models provides types and functions for working with Alpha Vantage data.
+//
+// This file contains the table renderer used by the String methods
+// to print responses of the Alpha Vantage API.
+// For more information about Alpha Vantage API, see https://www.alphavantage.co/documentation/.
+
+Author: Mason Wheeler
+*/
+
+package models
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// columnGap is the number of spaces between two table columns.
+const columnGap = 2
+
+// stringPrecision holds the number of decimals set by SetStringPrecision, or -1 for the
+// defaults of each type.
+var stringPrecision atomic.Int32
+
+func init() {
+	stringPrecision.Store(-1)
+}
+
+// SetStringPrecision sets the number of decimals of the prices and values rendered by the
+// String methods, e.g. 6 for crypto or JPY pairs where sub-cent moves matter. A negative n
+// restores the defaults: 2 decimals for stocks and crypto, 4 for forex, commodities and market
+// movers. It is safe to call concurrently with String.
+func SetStringPrecision(n int) {
+	if n < 0 {
+		n = -1
+	}
+	stringPrecision.Store(int32(n))
+}
+
+// precision returns the number of decimals set by SetStringPrecision, or def if none is set.
+func precision(def int) int {
+	if n := stringPrecision.Load(); n >= 0 {
+		return int(n)
+	}
+	return def
+}
+
+// writeTable renders rows under headers with each column as wide as its widest cell.
+// Headers and the first (time) column are left-aligned, the numeric columns right-aligned.
+func writeTable(sb *strings.Builder, headers []string, rows [][]string) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = len(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) && len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	total := 0
+	for i, width := range widths {
+		total += width
+		if i > 0 {
+			total += columnGap
+		}
+	}
+
+	for i, header := range headers {
+		if i > 0 {
+			sb.WriteString(strings.Repeat(" ", columnGap))
+		}
+		sb.WriteString(header)
+		if i < len(headers)-1 {
+			sb.WriteString(strings.Repeat(" ", widths[i]-len(header)))
+		}
+	}
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat("=", total))
+	sb.WriteString("\n")
+
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				break
+			}
+			if i == 0 {
+				sb.WriteString(cell)
+				sb.WriteString(strings.Repeat(" ", widths[i]-len(cell)))
+				continue
+			}
+			sb.WriteString(strings.Repeat(" ", columnGap+widths[i]-len(cell)))
+			sb.WriteString(cell)
+		}
+		sb.WriteString("\n")
+	}
+}
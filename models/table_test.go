@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkTable checks that the table at the end of the String output s is aligned: every row is
+// as wide as the separator line and the header is no wider, whatever the magnitude of the values.
+func checkTable(t *testing.T, s string, rows int) {
+	t.Helper()
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	sep := -1
+	for i, line := range lines {
+		if line != "" && strings.Trim(line, "=") == "" {
+			sep = i
+		}
+	}
+	if sep < 1 || len(lines)-sep-1 != rows {
+		t.Fatalf("no table with %d rows in:\n%s", rows, s)
+	}
+	width := len(lines[sep])
+	if len(lines[sep-1]) > width {
+		t.Errorf("header is %d wide, separator %d:\n%s", len(lines[sep-1]), width, s)
+	}
+	for _, line := range lines[sep+1:] {
+		if len(line) != width {
+			t.Errorf("row %q is %d wide, want %d:\n%s", line, len(line), width, s)
+		}
+	}
+}
+
+func TestStringTablesAligned(t *testing.T) {
+	day := date(2024, 3, 15)
+	bars := []OHLCV{
+		{Timestamp: day, Open: 1.5, High: 2, Low: 1, Close: 1.75, Volume: 12},
+		{Timestamp: day.AddDate(0, 0, 1), Open: 61234567.25, High: 61234999.5, Low: 61234000, Close: 61234567.75, Volume: 98765432109},
+	}
+	adjusted := []AdjustedOHLCV{
+		{OHLCV: bars[0], AdjustedClose: 1.7, Dividend: 0},
+		{OHLCV: bars[1], AdjustedClose: 61234567.7, Dividend: 12345.67},
+	}
+	ohlc := []OHLC{
+		{Timestamp: day, Open: 0.0001, High: 0.0002, Low: 0.0001, Close: 0.0002},
+		{Timestamp: day.AddDate(0, 0, 1), Open: 151234.5678, High: 151300.1, Low: 151200, Close: 151250.25},
+	}
+	points := []DataPoint{{Date: day, Value: 0.5}, {Date: day.AddDate(0, 1, 0), Value: 1234567.125}}
+
+	tests := []fmt.Stringer{
+		TimeSeriesIntraday{TimeSeries: bars},
+		TimeSeriesDaily{TimeSeries: bars},
+		TimeSeriesDailyAdjusted{TimeSeries: adjusted},
+		TimeSeriesWeekly{TimeSeries: bars},
+		TimeSeriesWeeklyAdjusted{TimeSeries: adjusted},
+		TimeSeriesMonthly{TimeSeries: bars},
+		TimeSeriesMonthlyAdjusted{TimeSeries: adjusted},
+		FXTimeSeries{TimeSeries: ohlc},
+		CommoditySeries{Data: points},
+		EconomicSeries{Data: points},
+	}
+	for _, series := range tests {
+		t.Run(fmt.Sprintf("%T", series), func(t *testing.T) {
+			checkTable(t, series.String(), 2)
+		})
+	}
+}
+
+func TestStringFollowsSliceOrder(t *testing.T) {
+	daily := TimeSeriesDaily{TimeSeries: []OHLCV{
+		{Timestamp: date(2024, 3, 14), Close: 1},
+		{Timestamp: date(2024, 3, 15), Close: 2},
+	}}
+	s := daily.SortDescending().String()
+	if strings.Index(s, "2024-03-15") > strings.Index(s, "2024-03-14") {
+		t.Errorf("descending series rendered ascending:\n%s", s)
+	}
+}
+
+func TestTimeSeriesIntradayStringTime(t *testing.T) {
+	intraday := TimeSeriesIntraday{TimeSeries: []OHLCV{{Timestamp: time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)}}}
+	if !strings.Contains(intraday.String(), "2024-03-15 09:30:00") {
+		t.Errorf("intraday time missing from:\n%s", intraday.String())
+	}
+}
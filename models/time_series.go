@@ -11,9 +11,13 @@ Author: Mason Wheeler
 package models
 
 import (
+	"bytes"
 	"strings"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 	"sort"
 	"strconv"
@@ -30,16 +34,32 @@ type TimeSeriesMetaData struct {
     TimePeriod float64 `json:"5. Time Period,omitempty"`
     SeriesType        string `json:"6. Series Type,omitempty"`
     VolumeFactor      string `json:"6. Volume Factor (vFactor),omitempty"`
+    Adjusted          bool   `json:"-"` // intraday only: whether prices are split/dividend adjusted, from the request
 }
 
 
 // TimeSeriesParams represents the parameters for querying time series data
 type TimeSeriesParams struct {
 	Symbol        string
-	Interval      string
+	Interval      Interval
 	Month         interface{}
-	OutputSize    interface{}
-	DataType      interface{}
+	OutputSize    interface{} // OutputSize, *OutputSize, string or *string
+	DataType      DataType // DataTypeCSV is only supported by the quote; the series getters reject it
+	Adjusted      *bool // intraday only; nil leaves the API default (adjusted)
+	ExtendedHours *bool // intraday only; nil leaves the API default (extended hours included)
+	Entitlement   string // "realtime" or "delayed" for premium plans; empty leaves the API default
+}
+
+// Validate checks the parameters the API would otherwise answer with an opaque error message.
+// An empty Interval is accepted, as only the intraday function takes one.
+func (p TimeSeriesParams) Validate() error {
+	if p.Interval != "" && !p.Interval.IsValid() {
+		return invalidIntervalError(p.Interval)
+	}
+	if p.DataType != "" && !p.DataType.IsValid() {
+		return fmt.Errorf("invalid data type %q: must be json or csv", p.DataType)
+	}
+	return nil
 }
 
 // OHLCV represents the Open, High, Low, Close, and Volume data for a given timestamp.
@@ -49,7 +69,7 @@ type OHLCV struct {
 	High      float64   `json:"2. high,string"`
 	Low       float64   `json:"3. low,string"`
 	Close     float64   `json:"4. close,string"`
-	Volume    int       `json:"5. volume,string"`
+	Volume    int64     `json:"5. volume,string"`
 }
 
 // AdjustedOHLCV represents the Open, High, Low, Close, Adjusted Close, and Dividend data for a given timestamp.
@@ -115,64 +135,252 @@ type Quote struct {
     ChangePercent    string  `json:"10. change percent"`
 }
 
+// Reset clears the TimeSeriesIntraday so that it can be reused.
+func (t *TimeSeriesIntraday) Reset() {
+	*t = TimeSeriesIntraday{}
+}
+
+// Reset clears the TimeSeriesDaily so that it can be reused.
+func (t *TimeSeriesDaily) Reset() {
+	*t = TimeSeriesDaily{}
+}
+
+// Reset clears the TimeSeriesDailyAdjusted so that it can be reused.
+func (t *TimeSeriesDailyAdjusted) Reset() {
+	*t = TimeSeriesDailyAdjusted{}
+}
+
+// Reset clears the TimeSeriesWeekly so that it can be reused.
+func (t *TimeSeriesWeekly) Reset() {
+	*t = TimeSeriesWeekly{}
+}
+
+// Reset clears the TimeSeriesWeeklyAdjusted so that it can be reused.
+func (t *TimeSeriesWeeklyAdjusted) Reset() {
+	*t = TimeSeriesWeeklyAdjusted{}
+}
+
+// Reset clears the TimeSeriesMonthly so that it can be reused.
+func (t *TimeSeriesMonthly) Reset() {
+	*t = TimeSeriesMonthly{}
+}
+
+// Reset clears the TimeSeriesMonthlyAdjusted so that it can be reused.
+func (t *TimeSeriesMonthlyAdjusted) Reset() {
+	*t = TimeSeriesMonthlyAdjusted{}
+}
+
+// Clone returns a copy of the TimeSeriesIntraday that shares no bars with t.
+func (t TimeSeriesIntraday) Clone() TimeSeriesIntraday {
+	return TimeSeriesIntraday{MetaData: t.MetaData, TimeSeries: append([]OHLCV(nil), t.TimeSeries...)}
+}
+
+// Clone returns a copy of the TimeSeriesDaily that shares no bars with t.
+func (t TimeSeriesDaily) Clone() TimeSeriesDaily {
+	return TimeSeriesDaily{MetaData: t.MetaData, TimeSeries: append([]OHLCV(nil), t.TimeSeries...)}
+}
+
+// Clone returns a copy of the TimeSeriesDailyAdjusted that shares no bars with t.
+func (t TimeSeriesDailyAdjusted) Clone() TimeSeriesDailyAdjusted {
+	return TimeSeriesDailyAdjusted{MetaData: t.MetaData, TimeSeries: append([]AdjustedOHLCV(nil), t.TimeSeries...)}
+}
+
+// Clone returns a copy of the TimeSeriesWeekly that shares no bars with t.
+func (t TimeSeriesWeekly) Clone() TimeSeriesWeekly {
+	return TimeSeriesWeekly{MetaData: t.MetaData, TimeSeries: append([]OHLCV(nil), t.TimeSeries...)}
+}
+
+// Clone returns a copy of the TimeSeriesWeeklyAdjusted that shares no bars with t.
+func (t TimeSeriesWeeklyAdjusted) Clone() TimeSeriesWeeklyAdjusted {
+	return TimeSeriesWeeklyAdjusted{MetaData: t.MetaData, TimeSeries: append([]AdjustedOHLCV(nil), t.TimeSeries...)}
+}
+
+// Clone returns a copy of the TimeSeriesMonthly that shares no bars with t.
+func (t TimeSeriesMonthly) Clone() TimeSeriesMonthly {
+	return TimeSeriesMonthly{MetaData: t.MetaData, TimeSeries: append([]OHLCV(nil), t.TimeSeries...)}
+}
+
+// Clone returns a copy of the TimeSeriesMonthlyAdjusted that shares no bars with t.
+func (t TimeSeriesMonthlyAdjusted) Clone() TimeSeriesMonthlyAdjusted {
+	return TimeSeriesMonthlyAdjusted{MetaData: t.MetaData, TimeSeries: append([]AdjustedOHLCV(nil), t.TimeSeries...)}
+}
+
+// sortDescending returns a copy of bars ordered newest first. The sort is stable, so bars
+// sharing a timestamp keep their relative order.
+func sortDescending[T any](bars []T, timestamp func(T) time.Time) []T {
+	sorted := append([]T(nil), bars...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return timestamp(sorted[i]).After(timestamp(sorted[j]))
+	})
+	return sorted
+}
+
+// SortDescending returns a copy of the TimeSeriesIntraday with the bars newest first, which String,
+// Records and WriteCSV then follow. The parsed series stays ascending: At, Latest and the
+// analytics helpers expect that order, so use the copy for display and export.
+func (t TimeSeriesIntraday) SortDescending() TimeSeriesIntraday {
+	return TimeSeriesIntraday{MetaData: t.MetaData, TimeSeries: sortDescending(t.TimeSeries, ohlcvTime)}
+}
+
+// SortDescending returns a copy of the TimeSeriesDaily with the bars newest first, like
+// TimeSeriesIntraday.SortDescending.
+func (t TimeSeriesDaily) SortDescending() TimeSeriesDaily {
+	return TimeSeriesDaily{MetaData: t.MetaData, TimeSeries: sortDescending(t.TimeSeries, ohlcvTime)}
+}
+
+// SortDescending returns a copy of the TimeSeriesDailyAdjusted with the bars newest first, like
+// TimeSeriesIntraday.SortDescending.
+func (t TimeSeriesDailyAdjusted) SortDescending() TimeSeriesDailyAdjusted {
+	return TimeSeriesDailyAdjusted{MetaData: t.MetaData, TimeSeries: sortDescending(t.TimeSeries, adjustedOHLCVTime)}
+}
+
+// SortDescending returns a copy of the TimeSeriesWeekly with the bars newest first, like
+// TimeSeriesIntraday.SortDescending.
+func (t TimeSeriesWeekly) SortDescending() TimeSeriesWeekly {
+	return TimeSeriesWeekly{MetaData: t.MetaData, TimeSeries: sortDescending(t.TimeSeries, ohlcvTime)}
+}
+
+// SortDescending returns a copy of the TimeSeriesWeeklyAdjusted with the bars newest first, like
+// TimeSeriesIntraday.SortDescending.
+func (t TimeSeriesWeeklyAdjusted) SortDescending() TimeSeriesWeeklyAdjusted {
+	return TimeSeriesWeeklyAdjusted{MetaData: t.MetaData, TimeSeries: sortDescending(t.TimeSeries, adjustedOHLCVTime)}
+}
+
+// SortDescending returns a copy of the TimeSeriesMonthly with the bars newest first, like
+// TimeSeriesIntraday.SortDescending.
+func (t TimeSeriesMonthly) SortDescending() TimeSeriesMonthly {
+	return TimeSeriesMonthly{MetaData: t.MetaData, TimeSeries: sortDescending(t.TimeSeries, ohlcvTime)}
+}
+
+// SortDescending returns a copy of the TimeSeriesMonthlyAdjusted with the bars newest first, like
+// TimeSeriesIntraday.SortDescending.
+func (t TimeSeriesMonthlyAdjusted) SortDescending() TimeSeriesMonthlyAdjusted {
+	return TimeSeriesMonthlyAdjusted{MetaData: t.MetaData, TimeSeries: sortDescending(t.TimeSeries, adjustedOHLCVTime)}
+}
+
 // UnmarshalJSON is a custom unmarshaler for the TimeSeriesIntraday struct.
 func (t *TimeSeriesIntraday) UnmarshalJSON(data []byte) error {
+    // Start from scratch so that bars of a previous response do not accumulate
+    t.Reset()
+
     var raw map[string]interface{}
     if err := json.Unmarshal(data, &raw); err != nil {
  	   return err
     }
 
+	// Missing metadata fields (e.g. Output Size when month is set) are left empty
 	if metaData, ok := raw["Meta Data"].(map[string]interface{}); ok {
-		t.MetaData.Information = metaData["1. Information"].(string)
-		t.MetaData.Symbol = metaData["2. Symbol"].(string)
-		t.MetaData.LastRefreshed = metaData["3. Last Refreshed"].(string)
-		t.MetaData.Interval = metaData["4. Interval"].(string)
-		t.MetaData.OutputSize = metaData["5. Output Size"].(string)
-		t.MetaData.TimeZone = metaData["6. Time Zone"].(string)
+		t.MetaData.Information, _ = metaData["1. Information"].(string)
+		t.MetaData.Symbol, _ = metaData["2. Symbol"].(string)
+		t.MetaData.LastRefreshed, _ = metaData["3. Last Refreshed"].(string)
+		t.MetaData.Interval, _ = metaData["4. Interval"].(string)
+		t.MetaData.OutputSize, _ = metaData["5. Output Size"].(string)
+		t.MetaData.TimeZone, _ = metaData["6. Time Zone"].(string)
 	}
 
+	var errs ParseErrors
 	for key, value := range raw {
 		if strings.HasPrefix(key, "Time Series") {
-			tsData, ok := value.(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("expected map for time series data")
+			tsData, err := json.Marshal(value)
+			if err != nil {
+				return err
 			}
 
-			for k, v := range tsData {
-				timestamp, err := time.Parse("2006-01-02 15:04:05", k)
-				if err != nil {
-					return err
-				}
-
-				ohlcvData, err := json.Marshal(v)
-				if err != nil {
-					return err
-				}
-
-				var ohlcv OHLCV
-				ohlcv.Timestamp = timestamp
-				if err := json.Unmarshal(ohlcvData, &ohlcv); err != nil {
-					return err
-				}
-				t.TimeSeries = append(t.TimeSeries, ohlcv)
+			var rawBars map[string]json.RawMessage
+			if err := json.Unmarshal(tsData, &rawBars); err != nil {
+				return fmt.Errorf("expected map for time series data")
 			}
+
+			bars, barErrs := parseBars(rawBars, "2006-01-02 15:04:05", stampOHLCV)
+			t.TimeSeries = append(t.TimeSeries, bars...)
+			errs = append(errs, barErrs...)
 		}
 	}
 
-	// Sorting is now based only on timestamps within the TimeSeries
-	sort.SliceStable(t.TimeSeries, func(i, j int) bool {
-		return t.TimeSeries[i].Timestamp.Before(t.TimeSeries[j].Timestamp)
+	sortBars(t.TimeSeries)
+
+	return errs.err()
+}
+
+// parseBars decodes the bars of a time series section keyed by timestamps in layout. Bars
+// whose timestamp or values cannot be parsed are skipped and reported in the ParseErrors.
+func parseBars[T any](rawBars map[string]json.RawMessage, layout string, stamp func(*T, time.Time)) ([]T, ParseErrors) {
+	var errs ParseErrors
+	bars := make([]T, 0, len(rawBars))
+	for key, rawBar := range rawBars {
+		timestamp, err := time.Parse(layout, key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var bar T
+		if err := json.Unmarshal(rawBar, &bar); err != nil {
+			errs = append(errs, fmt.Errorf("error parsing bar %s: %v", key, err))
+			continue
+		}
+		stamp(&bar, timestamp)
+		bars = append(bars, bar)
+	}
+	return bars, errs
+}
+
+// sortBars sorts the bars ascending by timestamp. Bars sharing a timestamp, e.g. after
+// merging overlapping responses, are ordered by their values, so the result does not depend
+// on the order of the input, which for decoded maps is random.
+func sortBars(bars []OHLCV) {
+	sort.SliceStable(bars, func(i, j int) bool {
+		return barLess(bars[i], bars[j])
 	})
+}
 
-	return nil
+// sortAdjustedBars is sortBars for adjusted bars.
+func sortAdjustedBars(bars []AdjustedOHLCV) {
+	sort.SliceStable(bars, func(i, j int) bool {
+		a, b := bars[i], bars[j]
+		if a.OHLCV != b.OHLCV {
+			return barLess(a.OHLCV, b.OHLCV)
+		}
+		if a.AdjustedClose != b.AdjustedClose {
+			return a.AdjustedClose < b.AdjustedClose
+		}
+		return a.Dividend < b.Dividend
+	})
+}
+
+// barLess orders two bars by timestamp, then by open, high, low, close and volume.
+func barLess(a, b OHLCV) bool {
+	switch {
+	case !a.Timestamp.Equal(b.Timestamp):
+		return a.Timestamp.Before(b.Timestamp)
+	case a.Open != b.Open:
+		return a.Open < b.Open
+	case a.High != b.High:
+		return a.High < b.High
+	case a.Low != b.Low:
+		return a.Low < b.Low
+	case a.Close != b.Close:
+		return a.Close < b.Close
+	}
+	return a.Volume < b.Volume
+}
+
+func stampOHLCV(bar *OHLCV, timestamp time.Time) {
+	bar.Timestamp = timestamp
+}
+
+func stampAdjustedOHLCV(bar *AdjustedOHLCV, timestamp time.Time) {
+	bar.Timestamp = timestamp
 }
 
 // UnmarshalJSON is a custom unmarshaler for the TimeSeriesDaily struct.
 func (ts *TimeSeriesDaily) UnmarshalJSON(data []byte) error {
+    ts.Reset()
+
     // Define a helper struct to use the default unmarshal
     type Alias TimeSeriesDaily
     aux := &struct {
-        RawTimeSeries map[string]OHLCV `json:"Time Series (Daily)"`
+        RawTimeSeries map[string]json.RawMessage `json:"Time Series (Daily)"`
         *Alias
     }{
         Alias: (*Alias)(ts),
@@ -184,30 +392,22 @@ func (ts *TimeSeriesDaily) UnmarshalJSON(data []byte) error {
     }
 
     // Convert the irregular map into a slice
-    ts.TimeSeries = make([]OHLCV, 0, len(aux.RawTimeSeries))
-    for dateStr, ohlcv := range aux.RawTimeSeries {
-        t, err := time.Parse("2006-01-02", dateStr)
-        if err != nil {
-            return err
-        }
-        ohlcv.Timestamp = t
-        ts.TimeSeries = append(ts.TimeSeries, ohlcv)
-    }
+    var errs ParseErrors
+    ts.TimeSeries, errs = parseBars(aux.RawTimeSeries, "2006-01-02", stampOHLCV)
 
-    // Sort the time series based on the timestamp
-    sort.Slice(ts.TimeSeries, func(i, j int) bool {
-        return ts.TimeSeries[i].Timestamp.Before(ts.TimeSeries[j].Timestamp)
-    })
+    sortBars(ts.TimeSeries)
 
-    return nil
+    return errs.err()
 }
 
 // UnmarshalJSON is a custom unmarshaler for the TimeSeriesDailyAdjusted struct.
 func (ts *TimeSeriesDailyAdjusted) UnmarshalJSON(data []byte) error {
+    ts.Reset()
+
     // Define a helper struct to use the default unmarshal
     type Alias TimeSeriesDailyAdjusted
     aux := &struct {
-        RawTimeSeries map[string]AdjustedOHLCV `json:"Time Series (Daily Adjusted)"`
+        RawTimeSeries map[string]json.RawMessage `json:"Time Series (Daily Adjusted)"`
         *Alias
     }{
         Alias: (*Alias)(ts),
@@ -219,30 +419,22 @@ func (ts *TimeSeriesDailyAdjusted) UnmarshalJSON(data []byte) error {
     }
 
     // Convert the irregular map into a slice
-    ts.TimeSeries = make([]AdjustedOHLCV, 0, len(aux.RawTimeSeries))
-    for dateStr, ohlcv := range aux.RawTimeSeries {
-        t, err := time.Parse("2006-01-02", dateStr)
-        if err != nil {
-            return err
-        }
-        ohlcv.Timestamp = t
-        ts.TimeSeries = append(ts.TimeSeries, ohlcv)
-    }
+    var errs ParseErrors
+    ts.TimeSeries, errs = parseBars(aux.RawTimeSeries, "2006-01-02", stampAdjustedOHLCV)
 
-    // Sort the time series based on the timestamp
-    sort.Slice(ts.TimeSeries, func(i, j int) bool {
-        return ts.TimeSeries[i].Timestamp.Before(ts.TimeSeries[j].Timestamp)
-    })
+    sortAdjustedBars(ts.TimeSeries)
 
-    return nil
+    return errs.err()
 }
 
 // UnmarshalJSON is a custom unmarshaler for the TimeSeriesWeekly struct.
 func (ts *TimeSeriesWeekly) UnmarshalJSON(data []byte) error {
+    ts.Reset()
+
     // Define a helper struct to use the default unmarshal
     type Alias TimeSeriesWeekly
     aux := &struct {
-        RawTimeSeries map[string]OHLCV `json:"Weekly Time Series"`
+        RawTimeSeries map[string]json.RawMessage `json:"Weekly Time Series"`
         *Alias
     }{
         Alias: (*Alias)(ts),
@@ -252,29 +444,22 @@ func (ts *TimeSeriesWeekly) UnmarshalJSON(data []byte) error {
         return err
     }
 
-    ts.TimeSeries = make([]OHLCV, 0, len(aux.RawTimeSeries))
-    for dateStr, ohlcv := range aux.RawTimeSeries {
-        t, err := time.Parse("2006-01-02", dateStr)
-        if err != nil {
-            return err
-        }
-        ohlcv.Timestamp = t
-        ts.TimeSeries = append(ts.TimeSeries, ohlcv)
-    }
+    var errs ParseErrors
+    ts.TimeSeries, errs = parseBars(aux.RawTimeSeries, "2006-01-02", stampOHLCV)
 
-    sort.Slice(ts.TimeSeries, func(i, j int) bool {
-        return ts.TimeSeries[i].Timestamp.Before(ts.TimeSeries[j].Timestamp)
-    })
+    sortBars(ts.TimeSeries)
 
-    return nil
+    return errs.err()
 }
 
 // UnmarshalJSON is a custom unmarshaler for the TimeSeriesWeeklyAdjusted struct.
 func (ts *TimeSeriesWeeklyAdjusted) UnmarshalJSON(data []byte) error {
+    ts.Reset()
+
     // Define a helper struct to use the default unmarshal
     type Alias TimeSeriesWeeklyAdjusted
     aux := &struct {
-        RawTimeSeries map[string]AdjustedOHLCV `json:"Weekly Adjusted Time Series"`
+        RawTimeSeries map[string]json.RawMessage `json:"Weekly Adjusted Time Series"`
         *Alias
     }{
         Alias: (*Alias)(ts),
@@ -284,29 +469,22 @@ func (ts *TimeSeriesWeeklyAdjusted) UnmarshalJSON(data []byte) error {
         return err
     }
 
-    ts.TimeSeries = make([]AdjustedOHLCV, 0, len(aux.RawTimeSeries))
-    for dateStr, ohlcv := range aux.RawTimeSeries {
-        t, err := time.Parse("2006-01-02", dateStr)
-        if err != nil {
-            return err
-        }
-        ohlcv.Timestamp = t
-        ts.TimeSeries = append(ts.TimeSeries, ohlcv)
-    }
+    var errs ParseErrors
+    ts.TimeSeries, errs = parseBars(aux.RawTimeSeries, "2006-01-02", stampAdjustedOHLCV)
 
-    sort.Slice(ts.TimeSeries, func(i, j int) bool {
-        return ts.TimeSeries[i].Timestamp.Before(ts.TimeSeries[j].Timestamp)
-    })
+    sortAdjustedBars(ts.TimeSeries)
 
-    return nil
+    return errs.err()
 }
 
 // UnmarshalJSON is a custom unmarshaler for the TimeSeriesMonthly struct.
 func (ts *TimeSeriesMonthly) UnmarshalJSON(data []byte) error {
+	ts.Reset()
+
 	// Define a helper struct to use the default unmarshal
 	type Alias TimeSeriesMonthly
 	aux := &struct {
-		RawTimeSeries map[string]OHLCV `json:"Monthly Time Series"`
+		RawTimeSeries map[string]json.RawMessage `json:"Monthly Time Series"`
 		*Alias
 	}{
 		Alias: (*Alias)(ts),
@@ -316,29 +494,22 @@ func (ts *TimeSeriesMonthly) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	ts.TimeSeries = make([]OHLCV, 0, len(aux.RawTimeSeries))
-	for dateStr, ohlcv := range aux.RawTimeSeries {
-		t, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			return err
-		}
-		ohlcv.Timestamp = t
-		ts.TimeSeries = append(ts.TimeSeries, ohlcv)
-	}
+	var errs ParseErrors
+	ts.TimeSeries, errs = parseBars(aux.RawTimeSeries, "2006-01-02", stampOHLCV)
 
-	sort.Slice(ts.TimeSeries, func(i, j int) bool {
-		return ts.TimeSeries[i].Timestamp.Before(ts.TimeSeries[j].Timestamp)
-	})
+	sortBars(ts.TimeSeries)
 
-	return nil
+	return errs.err()
 }
 
 // UnmarshalJSON is a custom unmarshaler for the TimeSeriesMonthlyAdjusted struct.
 func (ts *TimeSeriesMonthlyAdjusted) UnmarshalJSON(data []byte) error {
+	ts.Reset()
+
 	// Define a helper struct to use the default unmarshal
 	type Alias TimeSeriesMonthlyAdjusted
 	aux := &struct {
-		RawTimeSeries map[string]AdjustedOHLCV `json:"Monthly Adjusted Time Series"`
+		RawTimeSeries map[string]json.RawMessage `json:"Monthly Adjusted Time Series"`
 		*Alias
 	}{
 		Alias: (*Alias)(ts),
@@ -348,21 +519,12 @@ func (ts *TimeSeriesMonthlyAdjusted) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	ts.TimeSeries = make([]AdjustedOHLCV, 0, len(aux.RawTimeSeries))
-	for dateStr, ohlcv := range aux.RawTimeSeries {
-		t, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			return err
-		}
-		ohlcv.Timestamp = t
-		ts.TimeSeries = append(ts.TimeSeries, ohlcv)
-	}
+	var errs ParseErrors
+	ts.TimeSeries, errs = parseBars(aux.RawTimeSeries, "2006-01-02", stampAdjustedOHLCV)
 
-	sort.Slice(ts.TimeSeries, func(i, j int) bool {
-		return ts.TimeSeries[i].Timestamp.Before(ts.TimeSeries[j].Timestamp)
-	})
+	sortAdjustedBars(ts.TimeSeries)
 
-	return nil
+	return errs.err()
 }
 
 func (q *Quote) UnmarshalJSON(data []byte) error {
@@ -380,62 +542,221 @@ func (q *Quote) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	// Map each value from RawQuote to its corresponding field in the Quote struct
-	q.Symbol = aux.RawQuote["01. symbol"]
+	return parseQuoteFields(q, aux.RawQuote)
+}
 
-	open, err := strconv.ParseFloat(aux.RawQuote["02. open"], 64)
-	if err != nil {
-		return fmt.Errorf("error parsing 'open': %v", err)
-	}
-	q.Open = open
+// quoteCSVColumns maps the CSV columns of GLOBAL_QUOTE to the numbered keys of its JSON form.
+var quoteCSVColumns = map[string]string{
+	"symbol":        "01. symbol",
+	"open":          "02. open",
+	"high":          "03. high",
+	"low":           "04. low",
+	"price":         "05. price",
+	"volume":        "06. volume",
+	"latestDay":     "07. latest trading day",
+	"previousClose": "08. previous close",
+	"change":        "09. change",
+	"changePercent": "10. change percent",
+}
 
-	high, err := strconv.ParseFloat(aux.RawQuote["03. high"], 64)
+// ParseQuoteCSV parses the CSV body returned by GLOBAL_QUOTE with datatype=csv.
+func ParseQuoteCSV(data []byte) (Quote, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
 	if err != nil {
-		return fmt.Errorf("error parsing 'high': %v", err)
+		return Quote{}, err
+	}
+	if len(records) < 2 {
+		return Quote{}, fmt.Errorf("%w: quote CSV has no data row", ErrNoData)
 	}
-	q.High = high
 
-	low, err := strconv.ParseFloat(aux.RawQuote["04. low"], 64)
-	if err != nil {
-		return fmt.Errorf("error parsing 'low': %v", err)
+	raw := make(map[string]string)
+	for i, column := range records[0] {
+		if key, ok := quoteCSVColumns[column]; ok && i < len(records[1]) {
+			raw[key] = records[1][i]
+		}
 	}
-	q.Low = low
 
-	price, err := strconv.ParseFloat(aux.RawQuote["05. price"], 64)
-	if err != nil {
-		return fmt.Errorf("error parsing 'price': %v", err)
+	var q Quote
+	err = parseQuoteFields(&q, raw)
+	return q, err
+}
+
+// parseQuoteFields fills q from the numbered keys of a Global Quote. Fields may be missing
+// from partial quotes, e.g. the previous close before an IPO, and are then left at zero;
+// only values that are present but malformed are reported.
+func parseQuoteFields(q *Quote, raw map[string]string) error {
+	q.Symbol = raw["01. symbol"]
+
+	var errs []error
+	floatFields := []struct {
+		key  string
+		name string
+		dst  *float64
+	}{
+		{"02. open", "open", &q.Open},
+		{"03. high", "high", &q.High},
+		{"04. low", "low", &q.Low},
+		{"05. price", "price", &q.Price},
+		{"08. previous close", "previous close", &q.PreviousClose},
+		{"09. change", "change", &q.Change},
+	}
+	for _, field := range floatFields {
+		value := raw[field.key]
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error parsing '%s': %v", field.name, err))
+			continue
+		}
+		*field.dst = parsed
 	}
-	q.Price = price
 
-	volume, err := strconv.ParseInt(aux.RawQuote["06. volume"], 10, 64)
-	if err != nil {
-		return fmt.Errorf("error parsing 'volume': %v", err)
+	if value := raw["06. volume"]; value != "" {
+		volume, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error parsing 'volume': %v", err))
+		} else {
+			q.Volume = volume
+		}
 	}
-	q.Volume = volume
 
-	latestTradingDay, err := time.Parse("2006-01-02", aux.RawQuote["07. latest trading day"])
-	if err != nil {
-		return fmt.Errorf("error parsing 'latest trading day': %v", err)
+	if value := raw["07. latest trading day"]; value != "" {
+		latestTradingDay, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error parsing 'latest trading day': %v", err))
+		} else {
+			q.LatestTradingDay = latestTradingDay
+		}
 	}
-	q.LatestTradingDay = latestTradingDay
 
-	prevClose, err := strconv.ParseFloat(aux.RawQuote["08. previous close"], 64)
-	if err != nil {
-		return fmt.Errorf("error parsing 'previous close': %v", err)
+	q.ChangePercent = raw["10. change percent"]
+
+	return errors.Join(errs...)
+}
+
+
+// MarshalJSON emits the TimeSeriesIntraday in the timestamp-keyed layout of the API
+// so that it can be unmarshaled back into an equivalent struct.
+func (t TimeSeriesIntraday) MarshalJSON() ([]byte, error) {
+	key := "Time Series (" + t.MetaData.Interval + ")"
+	return marshalOHLCVSeries(t.MetaData, key, "2006-01-02 15:04:05", t.TimeSeries)
+}
+
+// MarshalJSON emits the TimeSeriesDaily in the timestamp-keyed layout of the API.
+func (ts TimeSeriesDaily) MarshalJSON() ([]byte, error) {
+	return marshalOHLCVSeries(ts.MetaData, "Time Series (Daily)", "2006-01-02", ts.TimeSeries)
+}
+
+// MarshalJSON emits the TimeSeriesDailyAdjusted in the timestamp-keyed layout of the API.
+func (ts TimeSeriesDailyAdjusted) MarshalJSON() ([]byte, error) {
+	return marshalAdjustedOHLCVSeries(ts.MetaData, "Time Series (Daily Adjusted)", ts.TimeSeries)
+}
+
+// MarshalJSON emits the TimeSeriesWeekly in the timestamp-keyed layout of the API.
+func (ts TimeSeriesWeekly) MarshalJSON() ([]byte, error) {
+	return marshalOHLCVSeries(ts.MetaData, "Weekly Time Series", "2006-01-02", ts.TimeSeries)
+}
+
+// MarshalJSON emits the TimeSeriesWeeklyAdjusted in the timestamp-keyed layout of the API.
+func (ts TimeSeriesWeeklyAdjusted) MarshalJSON() ([]byte, error) {
+	return marshalAdjustedOHLCVSeries(ts.MetaData, "Weekly Adjusted Time Series", ts.TimeSeries)
+}
+
+// MarshalJSON emits the TimeSeriesMonthly in the timestamp-keyed layout of the API.
+func (ts TimeSeriesMonthly) MarshalJSON() ([]byte, error) {
+	return marshalOHLCVSeries(ts.MetaData, "Monthly Time Series", "2006-01-02", ts.TimeSeries)
+}
+
+// MarshalJSON emits the TimeSeriesMonthlyAdjusted in the timestamp-keyed layout of the API.
+func (ts TimeSeriesMonthlyAdjusted) MarshalJSON() ([]byte, error) {
+	return marshalAdjustedOHLCVSeries(ts.MetaData, "Monthly Adjusted Time Series", ts.TimeSeries)
+}
+
+// marshalOHLCVSeries keys each bar by its timestamp formatted with layout under the given section name.
+func marshalOHLCVSeries(metaData TimeSeriesMetaData, key string, layout string, series []OHLCV) ([]byte, error) {
+	rawTimeSeries := make(map[string]OHLCV, len(series))
+	for _, v := range series {
+		rawTimeSeries[v.Timestamp.Format(layout)] = v
 	}
-	q.PreviousClose = prevClose
 
-	change, err := strconv.ParseFloat(aux.RawQuote["09. change"], 64)
-	if err != nil {
-		return fmt.Errorf("error parsing 'change': %v", err)
+	return json.Marshal(map[string]interface{}{
+		"Meta Data": metaData,
+		key:         rawTimeSeries,
+	})
+}
+
+// marshalAdjustedOHLCVSeries is marshalOHLCVSeries for the date-keyed adjusted series.
+func marshalAdjustedOHLCVSeries(metaData TimeSeriesMetaData, key string, series []AdjustedOHLCV) ([]byte, error) {
+	rawTimeSeries := make(map[string]AdjustedOHLCV, len(series))
+	for _, v := range series {
+		rawTimeSeries[v.Timestamp.Format("2006-01-02")] = v
 	}
-	q.Change = change
 
-	q.ChangePercent = aux.RawQuote["10. change percent"]
+	return json.Marshal(map[string]interface{}{
+		"Meta Data": metaData,
+		key:         rawTimeSeries,
+	})
+}
 
-	return nil
+// bulkQuoteKeys maps the unnumbered fields of REALTIME_BULK_QUOTES to the numbered keys of a
+// Global Quote.
+var bulkQuoteKeys = map[string]string{
+	"symbol":         "01. symbol",
+	"open":           "02. open",
+	"high":           "03. high",
+	"low":            "04. low",
+	"close":          "05. price",
+	"volume":         "06. volume",
+	"previous_close": "08. previous close",
+	"change":         "09. change",
+	"change_percent": "10. change percent",
 }
 
+// UnmarshalBulkQuotesJSON parses the "data" array of the REALTIME_BULK_QUOTES response into quotes.
+// The bulk fields are unnumbered ("close" rather than "05. price"), so Quote.UnmarshalJSON does not apply.
+// Each row is parsed like a Global Quote: missing fields are left at zero, and every row is kept.
+// Malformed values are left at zero too and reported together, per symbol, in a ParseErrors.
+func UnmarshalBulkQuotesJSON(quotes *[]Quote, data []byte) error {
+	var raw struct {
+		Data []map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed := make([]Quote, 0, len(raw.Data))
+	var errs ParseErrors
+	for _, rawQuote := range raw.Data {
+		fields := make(map[string]string, len(bulkQuoteKeys)+1)
+		for name, key := range bulkQuoteKeys {
+			fields[key] = rawQuote[name]
+		}
+
+		// The timestamp is a datetime such as "2024-03-15 16:00:00.000"; only the day is kept
+		timestamp := rawQuote["timestamp"]
+		if len(timestamp) > len("2006-01-02") {
+			timestamp = timestamp[:len("2006-01-02")]
+		}
+		fields["07. latest trading day"] = timestamp
+
+		var q Quote
+		if err := parseQuoteFields(&q, fields); err != nil {
+			errs = append(errs, fmt.Errorf("quote of %s: %w", q.Symbol, err))
+		}
+
+		// Match the "1.23%" format of GLOBAL_QUOTE
+		if q.ChangePercent != "" && !strings.HasSuffix(q.ChangePercent, "%") {
+			q.ChangePercent += "%"
+		}
+
+		parsed = append(parsed, q)
+	}
+
+	*quotes = parsed
+	return errs.err()
+}
 
 // Length returns the count of time series data entries.
 func (t *TimeSeriesIntraday) Length() int {
@@ -475,6 +796,7 @@ func (t *Quote) Length() int {
 // String representation of the TimeSeriesIntraday for custom printing.
 func (t TimeSeriesIntraday) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// First, print metadata
 	sb.WriteString(t.MetaData.Information + "\n")
@@ -485,21 +807,8 @@ func (t TimeSeriesIntraday) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", t.MetaData.TimeZone))
 	sb.WriteString("\n")
 
-	// Define headers for the dataframe-style table
-	headers := []string{"Time", "Open", "High", "Low", "Close", "Volume"}
-	sb.WriteString(fmt.Sprintf("%-25s", headers[0]))  // Increase width for Time
-	for _, header := range headers[1:] {
-		sb.WriteString(fmt.Sprintf("%-15s", header)) // Left-justify each header with a width of 20
-	}
-	sb.WriteString("\n")
-	sb.WriteString(strings.Repeat("=", 25 + (len(headers)-1)*15))  // Print line separator based on widths
-	sb.WriteString("\n")
-
-	// Loop through the TimeSeries slice
-	for _, v := range t.TimeSeries {
-		timeStr := v.Timestamp.Format("2006-01-02 15:04:05")
-		sb.WriteString(fmt.Sprintf("%-25s%-15.2f%-15.2f%-15.2f%-15.2f%-15d\n", timeStr, v.Open, v.High, v.Low, v.Close, v.Volume))
-	}
+	// Column widths follow the data so large prices and volumes stay aligned
+	writeTable(&sb, ohlcvHeaders, ohlcvRows(t.TimeSeries, "2006-01-02 15:04:05", prec))
 
 	return sb.String()
 }
@@ -507,6 +816,7 @@ func (t TimeSeriesIntraday) String() string {
 // String representation of the TimeSeriesDaily for custom printing.
 func (t TimeSeriesDaily) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// First, print metadata
 	sb.WriteString(t.MetaData.Information + "\n")
@@ -516,21 +826,8 @@ func (t TimeSeriesDaily) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", t.MetaData.TimeZone))
 	sb.WriteString("\n")
 
-	// Define headers for the dataframe-style table
-	headers := []string{"Time", "Open", "High", "Low", "Close", "Volume"}
-	sb.WriteString(fmt.Sprintf("%-25s", headers[0]))  // Increase width for Time
-	for _, header := range headers[1:] {
-		sb.WriteString(fmt.Sprintf("%-15s", header)) // Left-justify each header with a width of 20
-	}
-	sb.WriteString("\n")
-	sb.WriteString(strings.Repeat("=", 25 + (len(headers)-1)*15))  // Print line separator based on widths
-	sb.WriteString("\n")
-
-	// Loop through the TimeSeries slice
-	for _, v := range t.TimeSeries {
-		timeStr := v.Timestamp.Format("2006-01-02")
-		sb.WriteString(fmt.Sprintf("%-25s%-15.2f%-15.2f%-15.2f%-15.2f%-15d\n", timeStr, v.Open, v.High, v.Low, v.Close, v.Volume))
-	}
+	// Column widths follow the data so large prices and volumes stay aligned
+	writeTable(&sb, ohlcvHeaders, ohlcvRows(t.TimeSeries, "2006-01-02", prec))
 
 	return sb.String()
 }
@@ -538,6 +835,7 @@ func (t TimeSeriesDaily) String() string {
 // String representation of the TimeSeriesDailyAdjusted for custom printing.
 func (t TimeSeriesDailyAdjusted) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// First, print metadata
 	sb.WriteString(t.MetaData.Information + "\n")
@@ -547,21 +845,8 @@ func (t TimeSeriesDailyAdjusted) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", t.MetaData.TimeZone))
 	sb.WriteString("\n")
 
-	// Define headers for the dataframe-style table
-	headers := []string{"Time", "Open", "High", "Low", "Close", "Adjusted Close", "Volume", "Dividend"}
-	sb.WriteString(fmt.Sprintf("%-25s", headers[0]))  // Increase width for Time
-	for _, header := range headers[1:] {
-		sb.WriteString(fmt.Sprintf("%-15s", header)) // Left-justify each header with a width of 20
-	}
-	sb.WriteString("\n")
-	sb.WriteString(strings.Repeat("=", 25 + (len(headers)-1)*15))  // Print line separator based on widths
-	sb.WriteString("\n")
-
-	// Loop through the TimeSeries slice
-	for _, v := range t.TimeSeries {
-		timeStr := v.Timestamp.Format("2006-01-02")
-		sb.WriteString(fmt.Sprintf("%-25s%-15.2f%-15.2f%-15.2f%-15.2f%-15.2f%-15d%-15.2f\n", timeStr, v.Open, v.High, v.Low, v.Close, v.AdjustedClose, v.Volume, v.Dividend))
-	}
+	// Column widths follow the data so large prices and volumes stay aligned
+	writeTable(&sb, adjustedOHLCVHeaders, adjustedOHLCVRows(t.TimeSeries, prec))
 
 	return sb.String()
 }
@@ -569,6 +854,7 @@ func (t TimeSeriesDailyAdjusted) String() string {
 // String representation of the TimeSeriesWeekly for custom printing.
 func (t TimeSeriesWeekly) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// First, print metadata
 	sb.WriteString(t.MetaData.Information + "\n")
@@ -578,21 +864,8 @@ func (t TimeSeriesWeekly) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", t.MetaData.TimeZone))
 	sb.WriteString("\n")
 
-	// Define headers for the dataframe-style table
-	headers := []string{"Time", "Open", "High", "Low", "Close", "Volume"}
-	sb.WriteString(fmt.Sprintf("%-25s", headers[0]))  // Increase width for Time
-	for _, header := range headers[1:] {
-		sb.WriteString(fmt.Sprintf("%-15s", header)) // Left-justify each header with a width of 20
-	}
-	sb.WriteString("\n")
-	sb.WriteString(strings.Repeat("=", 25 + (len(headers)-1)*15))  // Print line separator based on widths
-	sb.WriteString("\n")
-
-	// Loop through the TimeSeries slice
-	for _, v := range t.TimeSeries {
-		timeStr := v.Timestamp.Format("2006-01-02")
-		sb.WriteString(fmt.Sprintf("%-25s%-15.2f%-15.2f%-15.2f%-15.2f%-15d\n", timeStr, v.Open, v.High, v.Low, v.Close, v.Volume))
-	}
+	// Column widths follow the data so large prices and volumes stay aligned
+	writeTable(&sb, ohlcvHeaders, ohlcvRows(t.TimeSeries, "2006-01-02", prec))
 
 	return sb.String()
 }
@@ -600,6 +873,7 @@ func (t TimeSeriesWeekly) String() string {
 // String representation of the TimeSeriesWeeklyAdjusted for custom printing.
 func (t TimeSeriesWeeklyAdjusted) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// First, print metadata
 	sb.WriteString(t.MetaData.Information + "\n")
@@ -609,21 +883,8 @@ func (t TimeSeriesWeeklyAdjusted) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", t.MetaData.TimeZone))
 	sb.WriteString("\n")
 
-	// Define headers for the dataframe-style table
-	headers := []string{"Time", "Open", "High", "Low", "Close", "Adjusted Close", "Volume", "Dividend"}
-	sb.WriteString(fmt.Sprintf("%-25s", headers[0]))  // Increase width for Time
-	for _, header := range headers[1:] {
-		sb.WriteString(fmt.Sprintf("%-15s", header)) // Left-justify each header with a width of 20
-	}
-	sb.WriteString("\n")
-	sb.WriteString(strings.Repeat("=", 25 + (len(headers)-1)*15))  // Print line separator based on widths
-	sb.WriteString("\n")
-
-	// Loop through the TimeSeries slice
-	for _, v := range t.TimeSeries {
-		timeStr := v.Timestamp.Format("2006-01-02")
-		sb.WriteString(fmt.Sprintf("%-25s%-15.2f%-15.2f%-15.2f%-15.2f%-15.2f%-15d%-15.2f\n", timeStr, v.Open, v.High, v.Low, v.Close, v.AdjustedClose, v.Volume, v.Dividend))
-	}
+	// Column widths follow the data so large prices and volumes stay aligned
+	writeTable(&sb, adjustedOHLCVHeaders, adjustedOHLCVRows(t.TimeSeries, prec))
 
 	return sb.String()
 }
@@ -631,6 +892,7 @@ func (t TimeSeriesWeeklyAdjusted) String() string {
 // String representation of the TimeSeriesMonthly for custom printing.
 func (t TimeSeriesMonthly) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// First, print metadata
 	sb.WriteString(t.MetaData.Information + "\n")
@@ -640,21 +902,8 @@ func (t TimeSeriesMonthly) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", t.MetaData.TimeZone))
 	sb.WriteString("\n")
 
-	// Define headers for the dataframe-style table
-	headers := []string{"Time", "Open", "High", "Low", "Close", "Volume"}
-	sb.WriteString(fmt.Sprintf("%-25s", headers[0]))  // Increase width for Time
-	for _, header := range headers[1:] {
-		sb.WriteString(fmt.Sprintf("%-15s", header)) // Left-justify each header with a width of 20
-	}
-	sb.WriteString("\n")
-	sb.WriteString(strings.Repeat("=", 25 + (len(headers)-1)*15))  // Print line separator based on widths
-	sb.WriteString("\n")
-
-	// Loop through the TimeSeries slice
-	for _, v := range t.TimeSeries {
-		timeStr := v.Timestamp.Format("2006-01-02")
-		sb.WriteString(fmt.Sprintf("%-25s%-15.2f%-15.2f%-15.2f%-15.2f%-15d\n", timeStr, v.Open, v.High, v.Low, v.Close, v.Volume))
-	}
+	// Column widths follow the data so large prices and volumes stay aligned
+	writeTable(&sb, ohlcvHeaders, ohlcvRows(t.TimeSeries, "2006-01-02", prec))
 
 	return sb.String()
 }
@@ -662,6 +911,7 @@ func (t TimeSeriesMonthly) String() string {
 // String representation of the TimeSeriesMonthlyAdjusted for custom printing.
 func (t TimeSeriesMonthlyAdjusted) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// First, print metadata
 	sb.WriteString(t.MetaData.Information + "\n")
@@ -671,40 +921,779 @@ func (t TimeSeriesMonthlyAdjusted) String() string {
 	sb.WriteString(fmt.Sprintf("Time Zone: %s\n", t.MetaData.TimeZone))
 	sb.WriteString("\n")
 
-	// Define headers for the dataframe-style table
-	headers := []string{"Time", "Open", "High", "Low", "Close", "Adjusted Close", "Volume", "Dividend"}
-	sb.WriteString(fmt.Sprintf("%-25s", headers[0]))  // Increase width for Time
-	for _, header := range headers[1:] {
-		sb.WriteString(fmt.Sprintf("%-15s", header)) // Left-justify each header with a width of 20
-	}
-	sb.WriteString("\n")
-	sb.WriteString(strings.Repeat("=", 25 + (len(headers)-1)*15))  // Print line separator based on widths
-	sb.WriteString("\n")
+	// Column widths follow the data so large prices and volumes stay aligned
+	writeTable(&sb, adjustedOHLCVHeaders, adjustedOHLCVRows(t.TimeSeries, prec))
 
-	// Loop through the TimeSeries slice
-	for _, v := range t.TimeSeries {
-		timeStr := v.Timestamp.Format("2006-01-02")
-		sb.WriteString(fmt.Sprintf("%-25s%-15.2f%-15.2f%-15.2f%-15.2f%-15.2f%-15d%-15.2f\n", timeStr, v.Open, v.High, v.Low, v.Close, v.AdjustedClose, v.Volume, v.Dividend))
+	return sb.String()
+}
+
+// ohlcvHeaders are the table headers of the String methods of the OHLCV series.
+var ohlcvHeaders = []string{"Time", "Open", "High", "Low", "Close", "Volume"}
+
+// adjustedOHLCVHeaders are the table headers of the String methods of the adjusted series.
+var adjustedOHLCVHeaders = []string{"Time", "Open", "High", "Low", "Close", "Adjusted Close", "Volume", "Dividend"}
+
+// ohlcvRows formats the bars as the table rows of ohlcvHeaders, with prices to prec decimals.
+func ohlcvRows(series []OHLCV, layout string, prec int) [][]string {
+	rows := make([][]string, 0, len(series))
+	for _, v := range series {
+		rows = append(rows, []string{
+			v.Timestamp.Format(layout),
+			fmt.Sprintf("%.*f", prec, v.Open),
+			fmt.Sprintf("%.*f", prec, v.High),
+			fmt.Sprintf("%.*f", prec, v.Low),
+			fmt.Sprintf("%.*f", prec, v.Close),
+			fmt.Sprintf("%d", v.Volume),
+		})
 	}
+	return rows
+}
 
-	return sb.String()
+// adjustedOHLCVRows formats the date-keyed adjusted bars as the table rows of adjustedOHLCVHeaders.
+func adjustedOHLCVRows(series []AdjustedOHLCV, prec int) [][]string {
+	rows := make([][]string, 0, len(series))
+	for _, v := range series {
+		rows = append(rows, []string{
+			v.Timestamp.Format("2006-01-02"),
+			fmt.Sprintf("%.*f", prec, v.Open),
+			fmt.Sprintf("%.*f", prec, v.High),
+			fmt.Sprintf("%.*f", prec, v.Low),
+			fmt.Sprintf("%.*f", prec, v.Close),
+			fmt.Sprintf("%.*f", prec, v.AdjustedClose),
+			fmt.Sprintf("%d", v.Volume),
+			fmt.Sprintf("%.*f", prec, v.Dividend),
+		})
+	}
+	return rows
 }
 
 // String representation of the Quote for custom printing.
 func (q Quote) String() string {
 	var sb strings.Builder
+	prec := precision(2)
 
 	// First, print metadata
 	sb.WriteString(fmt.Sprintf("Symbol: %s\n", q.Symbol))
-	sb.WriteString(fmt.Sprintf("Open: %.2f\n", q.Open))
-	sb.WriteString(fmt.Sprintf("High: %.2f\n", q.High))
-	sb.WriteString(fmt.Sprintf("Low: %.2f\n", q.Low))
-	sb.WriteString(fmt.Sprintf("Price: %.2f\n", q.Price))
+	sb.WriteString(fmt.Sprintf("Open: %.*f\n", prec, q.Open))
+	sb.WriteString(fmt.Sprintf("High: %.*f\n", prec, q.High))
+	sb.WriteString(fmt.Sprintf("Low: %.*f\n", prec, q.Low))
+	sb.WriteString(fmt.Sprintf("Price: %.*f\n", prec, q.Price))
 	sb.WriteString(fmt.Sprintf("Volume: %d\n", q.Volume))
 	sb.WriteString(fmt.Sprintf("Latest Trading Day: %s\n", q.LatestTradingDay.Format("2006-01-02")))
-	sb.WriteString(fmt.Sprintf("Previous Close: %.2f\n", q.PreviousClose))
-	sb.WriteString(fmt.Sprintf("Change: %.2f\n", q.Change))
+	sb.WriteString(fmt.Sprintf("Previous Close: %.*f\n", prec, q.PreviousClose))
+	sb.WriteString(fmt.Sprintf("Change: %.*f\n", prec, q.Change))
 	sb.WriteString(fmt.Sprintf("Change Percent: %s\n", q.ChangePercent))
 
 	return sb.String()
-}
\ No newline at end of file
+}
+
+// ToOHLCV converts the quote into a single daily bar stamped with LatestTradingDay, with Price
+// as the Close, e.g. to extend a TimeSeriesDaily up to the current session.
+func (q Quote) ToOHLCV() OHLCV {
+	return OHLCV{
+		Timestamp: q.LatestTradingDay,
+		Open:      q.Open,
+		High:      q.High,
+		Low:       q.Low,
+		Close:     q.Price,
+		Volume:    q.Volume,
+	}
+}
+
+// ChangePercentValue parses ChangePercent, e.g. "1.23%", into the number of percent, 1.23.
+func (q Quote) ChangePercentValue() (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(q.ChangePercent), "%"), 64)
+}
+
+// Age returns how long ago the quote's LatestTradingDay began, as of now. LatestTradingDay is
+// a date, so this detects quotes left over from a previous session, e.g. over a weekend or
+// holiday, but not staleness within the trading day.
+func (q Quote) Age(now time.Time) time.Duration {
+	return now.Sub(q.LatestTradingDay)
+}
+
+// IsStale reports whether the quote's Age as of now exceeds maxAge.
+func (q Quote) IsStale(maxAge time.Duration, now time.Time) bool {
+	return q.Age(now) > maxAge
+}
+
+// SortQuotesByChangePercent sorts quotes in place by their ChangePercentValue, ascending or
+// descending. The sort is stable and quotes whose change percent cannot be parsed go last
+// in either direction.
+func SortQuotesByChangePercent(quotes []Quote, ascending bool) {
+	sort.SliceStable(quotes, func(i, j int) bool {
+		a, errA := quotes[i].ChangePercentValue()
+		b, errB := quotes[j].ChangePercentValue()
+		if errA != nil || errB != nil {
+			return errA == nil && errB != nil
+		}
+		if ascending {
+			return a < b
+		}
+		return a > b
+	})
+}
+
+// Records returns the TimeSeriesIntraday as CSV records: a header row followed by one row per bar.
+func (t TimeSeriesIntraday) Records() [][]string {
+	return ohlcvRecords(t.TimeSeries, "2006-01-02 15:04:05")
+}
+
+// WriteCSV writes the TimeSeriesIntraday as CSV with a header row.
+func (t TimeSeriesIntraday) WriteCSV(w io.Writer) error {
+	return writeRecords(w, t.Records())
+}
+
+// Records returns the TimeSeriesDaily as CSV records: a header row followed by one row per bar.
+func (t TimeSeriesDaily) Records() [][]string {
+	return ohlcvRecords(t.TimeSeries, "2006-01-02")
+}
+
+// WriteCSV writes the TimeSeriesDaily as CSV with a header row.
+func (t TimeSeriesDaily) WriteCSV(w io.Writer) error {
+	return writeRecords(w, t.Records())
+}
+
+// Records returns the TimeSeriesDailyAdjusted as CSV records: a header row followed by one row per bar.
+func (t TimeSeriesDailyAdjusted) Records() [][]string {
+	return adjustedOHLCVRecords(t.TimeSeries, "2006-01-02")
+}
+
+// WriteCSV writes the TimeSeriesDailyAdjusted as CSV with a header row.
+func (t TimeSeriesDailyAdjusted) WriteCSV(w io.Writer) error {
+	return writeRecords(w, t.Records())
+}
+
+// Records returns the TimeSeriesWeekly as CSV records: a header row followed by one row per bar.
+func (t TimeSeriesWeekly) Records() [][]string {
+	return ohlcvRecords(t.TimeSeries, "2006-01-02")
+}
+
+// WriteCSV writes the TimeSeriesWeekly as CSV with a header row.
+func (t TimeSeriesWeekly) WriteCSV(w io.Writer) error {
+	return writeRecords(w, t.Records())
+}
+
+// Records returns the TimeSeriesWeeklyAdjusted as CSV records: a header row followed by one row per bar.
+func (t TimeSeriesWeeklyAdjusted) Records() [][]string {
+	return adjustedOHLCVRecords(t.TimeSeries, "2006-01-02")
+}
+
+// WriteCSV writes the TimeSeriesWeeklyAdjusted as CSV with a header row.
+func (t TimeSeriesWeeklyAdjusted) WriteCSV(w io.Writer) error {
+	return writeRecords(w, t.Records())
+}
+
+// Records returns the TimeSeriesMonthly as CSV records: a header row followed by one row per bar.
+func (t TimeSeriesMonthly) Records() [][]string {
+	return ohlcvRecords(t.TimeSeries, "2006-01-02")
+}
+
+// WriteCSV writes the TimeSeriesMonthly as CSV with a header row.
+func (t TimeSeriesMonthly) WriteCSV(w io.Writer) error {
+	return writeRecords(w, t.Records())
+}
+
+// Records returns the TimeSeriesMonthlyAdjusted as CSV records: a header row followed by one row per bar.
+func (t TimeSeriesMonthlyAdjusted) Records() [][]string {
+	return adjustedOHLCVRecords(t.TimeSeries, "2006-01-02")
+}
+
+// WriteCSV writes the TimeSeriesMonthlyAdjusted as CSV with a header row.
+func (t TimeSeriesMonthlyAdjusted) WriteCSV(w io.Writer) error {
+	return writeRecords(w, t.Records())
+}
+
+// writeRecords writes the records as CSV.
+func writeRecords(w io.Writer, records [][]string) error {
+	return csv.NewWriter(w).WriteAll(records)
+}
+
+// ohlcvRecords returns the header and one row per bar, formatting timestamps with layout.
+func ohlcvRecords(series []OHLCV, layout string) [][]string {
+	records := make([][]string, 0, len(series)+1)
+	records = append(records, []string{"timestamp", "open", "high", "low", "close", "volume"})
+	for _, v := range series {
+		records = append(records, []string{
+			v.Timestamp.Format(layout),
+			strconv.FormatFloat(v.Open, 'f', -1, 64),
+			strconv.FormatFloat(v.High, 'f', -1, 64),
+			strconv.FormatFloat(v.Low, 'f', -1, 64),
+			strconv.FormatFloat(v.Close, 'f', -1, 64),
+			strconv.FormatInt(v.Volume, 10),
+		})
+	}
+	return records
+}
+
+// adjustedOHLCVRecords is ohlcvRecords with the adjusted close and dividend columns.
+func adjustedOHLCVRecords(series []AdjustedOHLCV, layout string) [][]string {
+	records := make([][]string, 0, len(series)+1)
+	records = append(records, []string{"timestamp", "open", "high", "low", "close", "adjusted_close", "volume", "dividend"})
+	for _, v := range series {
+		records = append(records, []string{
+			v.Timestamp.Format(layout),
+			strconv.FormatFloat(v.Open, 'f', -1, 64),
+			strconv.FormatFloat(v.High, 'f', -1, 64),
+			strconv.FormatFloat(v.Low, 'f', -1, 64),
+			strconv.FormatFloat(v.Close, 'f', -1, 64),
+			strconv.FormatFloat(v.AdjustedClose, 'f', -1, 64),
+			strconv.FormatInt(v.Volume, 10),
+			strconv.FormatFloat(v.Dividend, 'f', -1, 64),
+		})
+	}
+	return records
+}
+
+// Columns returns the TimeSeries as parallel slices, one per field.
+func (t TimeSeriesIntraday) Columns() (timestamps []time.Time, open, high, low, close []float64, volume []int64) {
+	return ohlcvColumns(t.TimeSeries)
+}
+
+// Columns returns the TimeSeries as parallel slices, one per field.
+func (t TimeSeriesDaily) Columns() (timestamps []time.Time, open, high, low, close []float64, volume []int64) {
+	return ohlcvColumns(t.TimeSeries)
+}
+
+// Columns returns the TimeSeries as parallel slices, one per field.
+func (t TimeSeriesDailyAdjusted) Columns() (timestamps []time.Time, open, high, low, close []float64, volume []int64, adjustedClose, dividend []float64) {
+	return adjustedOHLCVColumns(t.TimeSeries)
+}
+
+// Columns returns the TimeSeries as parallel slices, one per field.
+func (t TimeSeriesWeekly) Columns() (timestamps []time.Time, open, high, low, close []float64, volume []int64) {
+	return ohlcvColumns(t.TimeSeries)
+}
+
+// Columns returns the TimeSeries as parallel slices, one per field.
+func (t TimeSeriesWeeklyAdjusted) Columns() (timestamps []time.Time, open, high, low, close []float64, volume []int64, adjustedClose, dividend []float64) {
+	return adjustedOHLCVColumns(t.TimeSeries)
+}
+
+// Columns returns the TimeSeries as parallel slices, one per field.
+func (t TimeSeriesMonthly) Columns() (timestamps []time.Time, open, high, low, close []float64, volume []int64) {
+	return ohlcvColumns(t.TimeSeries)
+}
+
+// Columns returns the TimeSeries as parallel slices, one per field.
+func (t TimeSeriesMonthlyAdjusted) Columns() (timestamps []time.Time, open, high, low, close []float64, volume []int64, adjustedClose, dividend []float64) {
+	return adjustedOHLCVColumns(t.TimeSeries)
+}
+
+func ohlcvColumns(series []OHLCV) (timestamps []time.Time, open, high, low, close []float64, volume []int64) {
+	timestamps = make([]time.Time, len(series))
+	open = make([]float64, len(series))
+	high = make([]float64, len(series))
+	low = make([]float64, len(series))
+	close = make([]float64, len(series))
+	volume = make([]int64, len(series))
+
+	for i, v := range series {
+		timestamps[i] = v.Timestamp
+		open[i] = v.Open
+		high[i] = v.High
+		low[i] = v.Low
+		close[i] = v.Close
+		volume[i] = v.Volume
+	}
+	return
+}
+
+func adjustedOHLCVColumns(series []AdjustedOHLCV) (timestamps []time.Time, open, high, low, close []float64, volume []int64, adjustedClose, dividend []float64) {
+	timestamps = make([]time.Time, len(series))
+	open = make([]float64, len(series))
+	high = make([]float64, len(series))
+	low = make([]float64, len(series))
+	close = make([]float64, len(series))
+	volume = make([]int64, len(series))
+	adjustedClose = make([]float64, len(series))
+	dividend = make([]float64, len(series))
+
+	for i, v := range series {
+		timestamps[i] = v.Timestamp
+		open[i] = v.Open
+		high[i] = v.High
+		low[i] = v.Low
+		close[i] = v.Close
+		volume[i] = v.Volume
+		adjustedClose[i] = v.AdjustedClose
+		dividend[i] = v.Dividend
+	}
+	return
+}
+
+// Merge folds other into the series: bars are de-duplicated by timestamp, with the bar from
+// other winning on conflicts, and re-sorted ascending. The metadata is taken from whichever
+// series has the more recent LastRefreshed.
+func (t *TimeSeriesDaily) Merge(other TimeSeriesDaily) {
+	bars := make(map[time.Time]OHLCV, len(t.TimeSeries)+len(other.TimeSeries))
+	for _, v := range t.TimeSeries {
+		bars[v.Timestamp] = v
+	}
+	for _, v := range other.TimeSeries {
+		bars[v.Timestamp] = v
+	}
+
+	// LastRefreshed is an ISO date or datetime, so it orders lexically
+	if other.MetaData.LastRefreshed >= t.MetaData.LastRefreshed {
+		t.MetaData = other.MetaData
+	}
+
+	t.TimeSeries = make([]OHLCV, 0, len(bars))
+	for _, v := range bars {
+		t.TimeSeries = append(t.TimeSeries, v)
+	}
+	sortBars(t.TimeSeries)
+}
+
+// Merge folds other into the series like TimeSeriesDaily.Merge: bars are de-duplicated by
+// timestamp, with the bar from other winning, and the metadata of the more recent series kept.
+func (t *TimeSeriesIntraday) Merge(other TimeSeriesIntraday) {
+	bars := make(map[time.Time]OHLCV, len(t.TimeSeries)+len(other.TimeSeries))
+	for _, v := range t.TimeSeries {
+		bars[v.Timestamp] = v
+	}
+	for _, v := range other.TimeSeries {
+		bars[v.Timestamp] = v
+	}
+
+	if other.MetaData.LastRefreshed >= t.MetaData.LastRefreshed {
+		t.MetaData = other.MetaData
+	}
+
+	t.TimeSeries = make([]OHLCV, 0, len(bars))
+	for _, v := range bars {
+		t.TimeSeries = append(t.TimeSeries, v)
+	}
+	sortBars(t.TimeSeries)
+}
+
+// BarChange classifies a BarDiff.
+type BarChange string
+
+// Kinds of BarDiff.
+const (
+	BarAdded   BarChange = "added"   // only in the newer series
+	BarRemoved BarChange = "removed" // only in the older series
+	BarChanged BarChange = "changed" // in both, with different values
+)
+
+// BarDiff describes a bar that differs between two fetches of a series. Old is the zero OHLCV
+// for an added bar and New for a removed one.
+type BarDiff struct {
+	Timestamp time.Time
+	Change    BarChange
+	Old       OHLCV
+	New       OHLCV
+}
+
+// Diff compares the series with a later fetch of it, other, by timestamp and returns the bars
+// that were added, removed or restated, sorted by timestamp. It is empty if the bars match.
+func (t TimeSeriesDaily) Diff(other TimeSeriesDaily) []BarDiff {
+	old := make(map[time.Time]OHLCV, len(t.TimeSeries))
+	for _, v := range t.TimeSeries {
+		old[v.Timestamp] = v
+	}
+
+	var diffs []BarDiff
+	for _, v := range other.TimeSeries {
+		prev, ok := old[v.Timestamp]
+		switch {
+		case !ok:
+			diffs = append(diffs, BarDiff{Timestamp: v.Timestamp, Change: BarAdded, New: v})
+		case prev != v:
+			diffs = append(diffs, BarDiff{Timestamp: v.Timestamp, Change: BarChanged, Old: prev, New: v})
+		}
+		delete(old, v.Timestamp)
+	}
+	for ts, v := range old {
+		diffs = append(diffs, BarDiff{Timestamp: ts, Change: BarRemoved, Old: v})
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Timestamp.Before(diffs[j].Timestamp)
+	})
+	return diffs
+}
+
+// Resample rolls the bars up into buckets of width d, e.g. 1min bars into 15min candles, taking
+// the first Open, highest High, lowest Low, last Close and summed Volume of each bucket. Buckets
+// are aligned to midnight of the bar's own day, so 15min buckets start on the quarter hour and
+// never span two days; a bucket is labeled with its start time. Buckets without bars, such as
+// overnight gaps, are omitted and a partial trailing bucket is kept as is. The bars are expected
+// in ascending order, as returned by the unmarshaler. A non-positive d returns a copy of t.
+func (t TimeSeriesIntraday) Resample(d time.Duration) TimeSeriesIntraday {
+	resampled := TimeSeriesIntraday{MetaData: t.MetaData}
+	if d <= 0 {
+		resampled.TimeSeries = append([]OHLCV(nil), t.TimeSeries...)
+		return resampled
+	}
+
+	if d%time.Minute == 0 {
+		resampled.MetaData.Interval = fmt.Sprintf("%dmin", d/time.Minute)
+	} else {
+		resampled.MetaData.Interval = d.String()
+	}
+
+	for _, v := range t.TimeSeries {
+		y, m, day := v.Timestamp.Date()
+		midnight := time.Date(y, m, day, 0, 0, 0, 0, v.Timestamp.Location())
+		start := midnight.Add(v.Timestamp.Sub(midnight) / d * d)
+
+		n := len(resampled.TimeSeries)
+		if n > 0 && resampled.TimeSeries[n-1].Timestamp.Equal(start) {
+			bucket := &resampled.TimeSeries[n-1]
+			if v.High > bucket.High {
+				bucket.High = v.High
+			}
+			if v.Low < bucket.Low {
+				bucket.Low = v.Low
+			}
+			bucket.Close = v.Close
+			bucket.Volume += v.Volume
+			continue
+		}
+
+		v.Timestamp = start
+		resampled.TimeSeries = append(resampled.TimeSeries, v)
+	}
+
+	return resampled
+}
+
+// Gaps returns the [start, end] pairs of consecutive bars that are further apart than expected,
+// typically 24 * time.Hour. Saturdays and Sundays do not count towards the spacing, so the
+// regular Friday to Monday step is not reported; market holidays are. The bars are expected in
+// ascending order, as returned by the unmarshaler.
+func (t TimeSeriesDaily) Gaps(expected time.Duration) [][2]time.Time {
+	var gaps [][2]time.Time
+	for i := 1; i < len(t.TimeSeries); i++ {
+		prev, cur := t.TimeSeries[i-1].Timestamp, t.TimeSeries[i].Timestamp
+		if weekdaySpan(prev, cur) > expected {
+			gaps = append(gaps, [2]time.Time{prev, cur})
+		}
+	}
+	return gaps
+}
+
+// weekdaySpan returns the time from start to end minus a day for every Saturday and Sunday
+// strictly after start and not after end.
+func weekdaySpan(start, end time.Time) time.Duration {
+	span := end.Sub(start)
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			span -= 24 * time.Hour
+		}
+	}
+	return span
+}
+
+// ToWeekly rolls the daily bars up into ISO weeks (Monday to Sunday), taking the first Open,
+// highest High, lowest Low, last Close and summed Volume of each week. Like the
+// TIME_SERIES_WEEKLY endpoint, each week is stamped with its last trading day. The daily
+// timestamps are calendar dates in the series' own time zone, so weeks are cut on those dates
+// without conversion. The bars are expected in ascending order, as returned by the unmarshaler.
+func (t TimeSeriesDaily) ToWeekly() TimeSeriesWeekly {
+	weekly := TimeSeriesWeekly{MetaData: t.MetaData}
+	weekly.MetaData.Information = "Weekly Prices (open, high, low, close) and Volumes"
+	weekly.TimeSeries = rollUpBars(t.TimeSeries, func(ts time.Time) int {
+		year, week := ts.ISOWeek()
+		return year*100 + week
+	})
+	return weekly
+}
+
+// ToMonthly rolls the daily bars up into calendar months in the same way as ToWeekly; each
+// month is stamped with its last trading day, like the TIME_SERIES_MONTHLY endpoint.
+func (t TimeSeriesDaily) ToMonthly() TimeSeriesMonthly {
+	monthly := TimeSeriesMonthly{MetaData: t.MetaData}
+	monthly.MetaData.Information = "Monthly Prices (open, high, low, close) and Volumes"
+	monthly.TimeSeries = rollUpBars(t.TimeSeries, func(ts time.Time) int {
+		return ts.Year()*100 + int(ts.Month())
+	})
+	return monthly
+}
+
+// rollUpBars merges consecutive bars with the same period key into one bar stamped with the
+// timestamp of the last bar of the period.
+func rollUpBars(bars []OHLCV, period func(time.Time) int) []OHLCV {
+	var rolled []OHLCV
+	prevKey := 0
+	for _, v := range bars {
+		key := period(v.Timestamp)
+		n := len(rolled)
+		if n > 0 && key == prevKey {
+			bar := &rolled[n-1]
+			if v.High > bar.High {
+				bar.High = v.High
+			}
+			if v.Low < bar.Low {
+				bar.Low = v.Low
+			}
+			bar.Close = v.Close
+			bar.Volume += v.Volume
+			bar.Timestamp = v.Timestamp
+			continue
+		}
+
+		rolled = append(rolled, v)
+		prevKey = key
+	}
+	return rolled
+}
+
+// FullyAdjusted back-adjusts the open, high, low and close of every bar by its ratio of adjusted
+// close to close, so that the whole bar accounts for splits and dividends rather than only the
+// close. Volume is left as reported. Bars with a zero close cannot be scaled and are copied as is.
+func (t TimeSeriesDailyAdjusted) FullyAdjusted() TimeSeriesDaily {
+	daily := TimeSeriesDaily{MetaData: t.MetaData, TimeSeries: make([]OHLCV, len(t.TimeSeries))}
+	for i, v := range t.TimeSeries {
+		bar := v.OHLCV
+		if bar.Close != 0 {
+			ratio := v.AdjustedClose / bar.Close
+			bar.Open *= ratio
+			bar.High *= ratio
+			bar.Low *= ratio
+			bar.Close = v.AdjustedClose
+		}
+		daily.TimeSeries[i] = bar
+	}
+	return daily
+}
+
+// Localize reinterprets the timestamps in the zone named by MetaData.TimeZone, e.g.
+// "US/Eastern". The API sends wall-clock times in that zone, which the unmarshaler parses
+// as UTC; after Localize "2023-09-08 19:55:00" denotes 19:55 in New York. Timestamps that are
+// no longer in UTC are left alone, so calling Localize twice is harmless. It fails if the
+// zone cannot be loaded.
+func (t *TimeSeriesIntraday) Localize() error {
+	loc, err := time.LoadLocation(t.MetaData.TimeZone)
+	if err != nil {
+		return fmt.Errorf("error loading time zone %q: %v", t.MetaData.TimeZone, err)
+	}
+
+	for i, v := range t.TimeSeries {
+		if v.Timestamp.Location() != time.UTC {
+			continue
+		}
+		y, m, d := v.Timestamp.Date()
+		hh, mm, ss := v.Timestamp.Clock()
+		t.TimeSeries[i].Timestamp = time.Date(y, m, d, hh, mm, ss, v.Timestamp.Nanosecond(), loc)
+	}
+	return nil
+}
+
+// InLocation converts the timestamps to loc without changing the instants they denote. Call
+// Localize first so that the instants are correct to begin with.
+func (t *TimeSeriesIntraday) InLocation(loc *time.Location) {
+	for i := range t.TimeSeries {
+		t.TimeSeries[i].Timestamp = t.TimeSeries[i].Timestamp.In(loc)
+	}
+}
+
+// filterBars returns the bars for which keep returns true, in a new slice.
+func filterBars[T any](bars []T, keep func(T) bool) []T {
+	kept := make([]T, 0, len(bars))
+	for _, bar := range bars {
+		if keep(bar) {
+			kept = append(kept, bar)
+		}
+	}
+	return kept
+}
+
+// isZeroBar reports whether open, high, low and close are all zero, as for bad ticks.
+func isZeroBar(bar OHLCV) bool {
+	return bar.Open == 0 && bar.High == 0 && bar.Low == 0 && bar.Close == 0
+}
+
+func isZeroAdjustedBar(bar AdjustedOHLCV) bool {
+	return isZeroBar(bar.OHLCV)
+}
+
+// Filter returns a copy of the TimeSeriesIntraday holding only the bars for which keep returns true.
+func (t TimeSeriesIntraday) Filter(keep func(OHLCV) bool) TimeSeriesIntraday {
+	return TimeSeriesIntraday{MetaData: t.MetaData, TimeSeries: filterBars(t.TimeSeries, keep)}
+}
+
+// DropZeroBars returns a copy of the TimeSeriesIntraday without the bars whose open, high, low and
+// close are all zero, which would otherwise produce infinite returns.
+func (t TimeSeriesIntraday) DropZeroBars() TimeSeriesIntraday {
+	return t.Filter(func(bar OHLCV) bool { return !isZeroBar(bar) })
+}
+
+// Filter returns a copy of the TimeSeriesDaily holding only the bars for which keep returns true.
+func (t TimeSeriesDaily) Filter(keep func(OHLCV) bool) TimeSeriesDaily {
+	return TimeSeriesDaily{MetaData: t.MetaData, TimeSeries: filterBars(t.TimeSeries, keep)}
+}
+
+// DropZeroBars returns a copy of the TimeSeriesDaily without the bars whose open, high, low and
+// close are all zero, which would otherwise produce infinite returns.
+func (t TimeSeriesDaily) DropZeroBars() TimeSeriesDaily {
+	return t.Filter(func(bar OHLCV) bool { return !isZeroBar(bar) })
+}
+
+// Filter returns a copy of the TimeSeriesDailyAdjusted holding only the bars for which keep returns true.
+func (t TimeSeriesDailyAdjusted) Filter(keep func(AdjustedOHLCV) bool) TimeSeriesDailyAdjusted {
+	return TimeSeriesDailyAdjusted{MetaData: t.MetaData, TimeSeries: filterBars(t.TimeSeries, keep)}
+}
+
+// DropZeroBars returns a copy of the TimeSeriesDailyAdjusted without the bars whose open, high, low and
+// close are all zero, which would otherwise produce infinite returns.
+func (t TimeSeriesDailyAdjusted) DropZeroBars() TimeSeriesDailyAdjusted {
+	return t.Filter(func(bar AdjustedOHLCV) bool { return !isZeroAdjustedBar(bar) })
+}
+
+// Filter returns a copy of the TimeSeriesWeekly holding only the bars for which keep returns true.
+func (t TimeSeriesWeekly) Filter(keep func(OHLCV) bool) TimeSeriesWeekly {
+	return TimeSeriesWeekly{MetaData: t.MetaData, TimeSeries: filterBars(t.TimeSeries, keep)}
+}
+
+// DropZeroBars returns a copy of the TimeSeriesWeekly without the bars whose open, high, low and
+// close are all zero, which would otherwise produce infinite returns.
+func (t TimeSeriesWeekly) DropZeroBars() TimeSeriesWeekly {
+	return t.Filter(func(bar OHLCV) bool { return !isZeroBar(bar) })
+}
+
+// Filter returns a copy of the TimeSeriesWeeklyAdjusted holding only the bars for which keep returns true.
+func (t TimeSeriesWeeklyAdjusted) Filter(keep func(AdjustedOHLCV) bool) TimeSeriesWeeklyAdjusted {
+	return TimeSeriesWeeklyAdjusted{MetaData: t.MetaData, TimeSeries: filterBars(t.TimeSeries, keep)}
+}
+
+// DropZeroBars returns a copy of the TimeSeriesWeeklyAdjusted without the bars whose open, high, low and
+// close are all zero, which would otherwise produce infinite returns.
+func (t TimeSeriesWeeklyAdjusted) DropZeroBars() TimeSeriesWeeklyAdjusted {
+	return t.Filter(func(bar AdjustedOHLCV) bool { return !isZeroAdjustedBar(bar) })
+}
+
+// Filter returns a copy of the TimeSeriesMonthly holding only the bars for which keep returns true.
+func (t TimeSeriesMonthly) Filter(keep func(OHLCV) bool) TimeSeriesMonthly {
+	return TimeSeriesMonthly{MetaData: t.MetaData, TimeSeries: filterBars(t.TimeSeries, keep)}
+}
+
+// DropZeroBars returns a copy of the TimeSeriesMonthly without the bars whose open, high, low and
+// close are all zero, which would otherwise produce infinite returns.
+func (t TimeSeriesMonthly) DropZeroBars() TimeSeriesMonthly {
+	return t.Filter(func(bar OHLCV) bool { return !isZeroBar(bar) })
+}
+
+// Filter returns a copy of the TimeSeriesMonthlyAdjusted holding only the bars for which keep returns true.
+func (t TimeSeriesMonthlyAdjusted) Filter(keep func(AdjustedOHLCV) bool) TimeSeriesMonthlyAdjusted {
+	return TimeSeriesMonthlyAdjusted{MetaData: t.MetaData, TimeSeries: filterBars(t.TimeSeries, keep)}
+}
+
+// DropZeroBars returns a copy of the TimeSeriesMonthlyAdjusted without the bars whose open, high, low and
+// close are all zero, which would otherwise produce infinite returns.
+func (t TimeSeriesMonthlyAdjusted) DropZeroBars() TimeSeriesMonthlyAdjusted {
+	return t.Filter(func(bar AdjustedOHLCV) bool { return !isZeroAdjustedBar(bar) })
+}
+
+// searchBar binary-searches the bars, sorted ascending by the timestamp returned by stamp, for
+// the bar stamped exactly ts.
+func searchBar[T any](bars []T, ts time.Time, stamp func(T) time.Time) (T, bool) {
+	i := sort.Search(len(bars), func(i int) bool {
+		return !stamp(bars[i]).Before(ts)
+	})
+	if i < len(bars) && stamp(bars[i]).Equal(ts) {
+		return bars[i], true
+	}
+	var zero T
+	return zero, false
+}
+
+// dateOf returns the calendar date of ts at midnight UTC, the form of the daily, weekly and
+// monthly timestamps.
+func dateOf(ts time.Time) time.Time {
+	return time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func ohlcvTime(bar OHLCV) time.Time {
+	return bar.Timestamp
+}
+
+func adjustedOHLCVTime(bar AdjustedOHLCV) time.Time {
+	return bar.Timestamp
+}
+
+// At returns the bar stamped exactly ts and whether there is one.
+func (t TimeSeriesIntraday) At(ts time.Time) (OHLCV, bool) {
+	return searchBar(t.TimeSeries, ts, ohlcvTime)
+}
+
+// At returns the bar of the calendar date of ts, ignoring its time of day and zone, and
+// whether there is one.
+func (t TimeSeriesDaily) At(ts time.Time) (OHLCV, bool) {
+	return searchBar(t.TimeSeries, dateOf(ts), ohlcvTime)
+}
+
+// At returns the bar of the calendar date of ts, like TimeSeriesDaily.At.
+func (t TimeSeriesDailyAdjusted) At(ts time.Time) (AdjustedOHLCV, bool) {
+	return searchBar(t.TimeSeries, dateOf(ts), adjustedOHLCVTime)
+}
+
+// At returns the bar stamped with the calendar date of ts, the last trading day of its week,
+// like TimeSeriesDaily.At.
+func (t TimeSeriesWeekly) At(ts time.Time) (OHLCV, bool) {
+	return searchBar(t.TimeSeries, dateOf(ts), ohlcvTime)
+}
+
+// At returns the bar stamped with the calendar date of ts, like TimeSeriesWeekly.At.
+func (t TimeSeriesWeeklyAdjusted) At(ts time.Time) (AdjustedOHLCV, bool) {
+	return searchBar(t.TimeSeries, dateOf(ts), adjustedOHLCVTime)
+}
+
+// At returns the bar stamped with the calendar date of ts, the last trading day of its month,
+// like TimeSeriesDaily.At.
+func (t TimeSeriesMonthly) At(ts time.Time) (OHLCV, bool) {
+	return searchBar(t.TimeSeries, dateOf(ts), ohlcvTime)
+}
+
+// At returns the bar stamped with the calendar date of ts, like TimeSeriesMonthly.At.
+func (t TimeSeriesMonthlyAdjusted) At(ts time.Time) (AdjustedOHLCV, bool) {
+	return searchBar(t.TimeSeries, dateOf(ts), adjustedOHLCVTime)
+}
+
+// lastBar returns the last of bars, the most recent one as they are sorted ascending, and
+// whether there is one.
+func lastBar[T any](bars []T) (T, bool) {
+	if len(bars) == 0 {
+		var zero T
+		return zero, false
+	}
+	return bars[len(bars)-1], true
+}
+
+// Latest returns the most recent bar and whether the series has any.
+func (t TimeSeriesIntraday) Latest() (OHLCV, bool) {
+	return lastBar(t.TimeSeries)
+}
+
+// Latest returns the most recent bar and whether the series has any.
+func (t TimeSeriesDaily) Latest() (OHLCV, bool) {
+	return lastBar(t.TimeSeries)
+}
+
+// Latest returns the most recent bar and whether the series has any.
+func (t TimeSeriesDailyAdjusted) Latest() (AdjustedOHLCV, bool) {
+	return lastBar(t.TimeSeries)
+}
+
+// Latest returns the most recent bar and whether the series has any.
+func (t TimeSeriesWeekly) Latest() (OHLCV, bool) {
+	return lastBar(t.TimeSeries)
+}
+
+// Latest returns the most recent bar and whether the series has any.
+func (t TimeSeriesWeeklyAdjusted) Latest() (AdjustedOHLCV, bool) {
+	return lastBar(t.TimeSeries)
+}
+
+// Latest returns the most recent bar and whether the series has any.
+func (t TimeSeriesMonthly) Latest() (OHLCV, bool) {
+	return lastBar(t.TimeSeries)
+}
+
+// Latest returns the most recent bar and whether the series has any.
+func (t TimeSeriesMonthlyAdjusted) Latest() (AdjustedOHLCV, bool) {
+	return lastBar(t.TimeSeries)
+}
@@ -0,0 +1,478 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntradayMetaDataWithoutOutputSize(t *testing.T) {
+	body := []byte(`{
+		"Meta Data": {
+			"1. Information": "Intraday (5min) open, high, low, close prices and volume",
+			"2. Symbol": "IBM",
+			"3. Last Refreshed": "2023-01-31 19:55:00",
+			"4. Interval": "5min",
+			"6. Time Zone": 5
+		},
+		"Time Series (5min)": {
+			"2023-01-31 19:55:00": {"1. open": "134.0", "2. high": "134.1", "3. low": "133.9", "4. close": "134.0", "5. volume": "10"}
+		}
+	}`)
+
+	var intraday TimeSeriesIntraday
+	if err := json.Unmarshal(body, &intraday); err != nil {
+		t.Fatal(err)
+	}
+	if intraday.MetaData.OutputSize != "" {
+		t.Errorf("output size = %q, want empty", intraday.MetaData.OutputSize)
+	}
+	// A field of the wrong type is left empty as well
+	if intraday.MetaData.TimeZone != "" {
+		t.Errorf("time zone = %q, want empty", intraday.MetaData.TimeZone)
+	}
+	if intraday.MetaData.Symbol != "IBM" || intraday.MetaData.Interval != "5min" {
+		t.Errorf("unexpected metadata %+v", intraday.MetaData)
+	}
+	if len(intraday.TimeSeries) != 1 {
+		t.Errorf("got %d bars, want 1", len(intraday.TimeSeries))
+	}
+}
+
+func TestUnmarshalBulkQuotesJSON(t *testing.T) {
+	body := []byte(`{"endpoint": "Realtime Bulk Quotes", "data": [
+		{"symbol": "IBM", "timestamp": "2024-03-15 16:00:00.000", "open": "191.99", "high": "193.06", "low": "190.70", "close": "191.07", "volume": "8828184", "previous_close": "191.69", "change": "-0.62", "change_percent": "-0.3234"},
+		{"symbol": "NEWCO", "timestamp": "2024-03-15 16:00:00.000", "open": "21.50", "close": "22.87", "volume": "15872345", "change": ""},
+		{"symbol": "BAD", "timestamp": "2024-03-15 16:00:00.000", "open": "n/a", "close": "10.00", "volume": "5"}
+	]}`)
+
+	var quotes []Quote
+	err := UnmarshalBulkQuotesJSON(&quotes, body)
+
+	var parseErrs ParseErrors
+	if !errors.As(err, &parseErrs) || len(parseErrs) != 1 || !strings.Contains(parseErrs[0].Error(), "BAD") {
+		t.Fatalf("err = %v, want one ParseErrors entry for BAD", err)
+	}
+	if len(quotes) != 3 {
+		t.Fatalf("got %d quotes, want 3", len(quotes))
+	}
+
+	ibm := quotes[0]
+	if ibm.Price != 191.07 || ibm.PreviousClose != 191.69 || ibm.Volume != 8828184 || ibm.ChangePercent != "-0.3234%" ||
+		!ibm.LatestTradingDay.Equal(date(2024, 3, 15)) {
+		t.Errorf("IBM = %+v", ibm)
+	}
+	// Missing and empty fields are left at zero without failing the row
+	newco := quotes[1]
+	if newco.Price != 22.87 || newco.High != 0 || newco.PreviousClose != 0 || newco.Change != 0 {
+		t.Errorf("NEWCO = %+v", newco)
+	}
+	// The malformed field is zero but the rest of the row is kept
+	bad := quotes[2]
+	if bad.Symbol != "BAD" || bad.Open != 0 || bad.Price != 10 || bad.Volume != 5 {
+		t.Errorf("BAD = %+v", bad)
+	}
+}
+
+func TestParseQuoteCSV(t *testing.T) {
+	body := "symbol,open,high,low,price,volume,latestDay,previousClose,change,changePercent\n" +
+		"IBM,147.49,148.38,146.9,147.68,2958765,2023-09-08,147.52,0.16,0.1085%\n"
+
+	quote, err := ParseQuoteCSV([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.Symbol != "IBM" || quote.Open != 147.49 || quote.Price != 147.68 || quote.Volume != 2958765 ||
+		quote.PreviousClose != 147.52 || quote.Change != 0.16 || quote.ChangePercent != "0.1085%" {
+		t.Errorf("unexpected quote %+v", quote)
+	}
+	if !quote.LatestTradingDay.Equal(date(2023, 9, 8)) {
+		t.Errorf("latest trading day = %v, want 2023-09-08", quote.LatestTradingDay)
+	}
+}
+
+func TestParseQuoteCSVNoRow(t *testing.T) {
+	_, err := ParseQuoteCSV([]byte("symbol,open,high,low,price,volume,latestDay,previousClose,change,changePercent\n"))
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("err = %v, want ErrNoData", err)
+	}
+}
+
+// roundTrip unmarshals data into a T, marshals the result and unmarshals that again,
+// returning both decoded values for comparison.
+func roundTrip[T any](t *testing.T, data []byte, unmarshal func(*T, []byte) error) (first, second T) {
+	t.Helper()
+	if err := unmarshal(&first, data); err != nil {
+		t.Fatal(err)
+	}
+	marshaled, err := json.Marshal(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unmarshal(&second, marshaled); err != nil {
+		t.Fatalf("unmarshal of %s: %v", marshaled, err)
+	}
+	return first, second
+}
+
+func jsonUnmarshal[T any](v *T, data []byte) error {
+	return json.Unmarshal(data, v)
+}
+
+func TestTimeSeriesMarshalRoundTrip(t *testing.T) {
+	const bar = `{"1. open": "1.5", "2. high": "2.25", "3. low": "1", "4. close": "2", "5. volume": "1200"}`
+	const adjustedBar = `{"1. open": "1.5", "2. high": "2.25", "3. low": "1", "4. close": "2", "5. adjusted close": "1.9", "5. volume": "1200", "7. dividend amount": "0.25"}`
+	series := func(section, bar string) []byte {
+		return []byte(`{"Meta Data": {"2. Symbol": "IBM"}, "` + section + `": {"2023-09-08": ` + bar + `, "2023-09-01": ` + bar + `}}`)
+	}
+
+	check := func(t *testing.T, first, second any, bars int) {
+		t.Helper()
+		if bars == 0 {
+			t.Fatal("no bars decoded before marshaling")
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("round trip changed the value:\n got %+v\nwant %+v", second, first)
+		}
+	}
+
+	t.Run("intraday fixture", func(t *testing.T) {
+		first, second := roundTrip(t, readFixture(t, "intraday.json"), jsonUnmarshal[TimeSeriesIntraday])
+		check(t, first, second, len(first.TimeSeries))
+	})
+	t.Run("daily fixture", func(t *testing.T) {
+		first, second := roundTrip(t, readFixture(t, "daily.json"), jsonUnmarshal[TimeSeriesDaily])
+		check(t, first, second, len(first.TimeSeries))
+	})
+	t.Run("daily adjusted", func(t *testing.T) {
+		first, second := roundTrip(t, series("Time Series (Daily Adjusted)", adjustedBar), jsonUnmarshal[TimeSeriesDailyAdjusted])
+		check(t, first, second, len(first.TimeSeries))
+		if first.TimeSeries[0].AdjustedClose != 1.9 || first.TimeSeries[0].Dividend != 0.25 {
+			t.Errorf("unexpected bar %+v", first.TimeSeries[0])
+		}
+	})
+	t.Run("weekly", func(t *testing.T) {
+		first, second := roundTrip(t, series("Weekly Time Series", bar), jsonUnmarshal[TimeSeriesWeekly])
+		check(t, first, second, len(first.TimeSeries))
+	})
+	t.Run("weekly adjusted", func(t *testing.T) {
+		first, second := roundTrip(t, series("Weekly Adjusted Time Series", adjustedBar), jsonUnmarshal[TimeSeriesWeeklyAdjusted])
+		check(t, first, second, len(first.TimeSeries))
+	})
+	t.Run("monthly", func(t *testing.T) {
+		first, second := roundTrip(t, series("Monthly Time Series", bar), jsonUnmarshal[TimeSeriesMonthly])
+		check(t, first, second, len(first.TimeSeries))
+	})
+	t.Run("monthly adjusted", func(t *testing.T) {
+		first, second := roundTrip(t, series("Monthly Adjusted Time Series", adjustedBar), jsonUnmarshal[TimeSeriesMonthlyAdjusted])
+		check(t, first, second, len(first.TimeSeries))
+	})
+}
+
+// bar returns an OHLCV at ts with every price set to price and a volume of 100.
+func bar(ts time.Time, price float64) OHLCV {
+	return OHLCV{Timestamp: ts, Open: price, High: price, Low: price, Close: price, Volume: 100}
+}
+
+func TestDailyMerge(t *testing.T) {
+	older := TimeSeriesDaily{
+		MetaData:   TimeSeriesMetaData{Symbol: "IBM", LastRefreshed: "2023-09-06"},
+		TimeSeries: []OHLCV{bar(date(2023, 9, 5), 1), bar(date(2023, 9, 6), 2)},
+	}
+	newer := TimeSeriesDaily{
+		MetaData:   TimeSeriesMetaData{Symbol: "IBM", LastRefreshed: "2023-09-08"},
+		TimeSeries: []OHLCV{bar(date(2023, 9, 8), 4), bar(date(2023, 9, 6), 2.5), bar(date(2023, 9, 7), 3)},
+	}
+
+	t.Run("overlap", func(t *testing.T) {
+		merged := older
+		merged.Merge(newer)
+		// The repeated 09-06 bar is kept once, from the merged-in series
+		checkBars(t, merged.TimeSeries, []OHLCV{
+			bar(date(2023, 9, 5), 1), bar(date(2023, 9, 6), 2.5), bar(date(2023, 9, 7), 3), bar(date(2023, 9, 8), 4),
+		})
+		if merged.MetaData.LastRefreshed != "2023-09-08" {
+			t.Errorf("LastRefreshed = %q, want the newer metadata", merged.MetaData.LastRefreshed)
+		}
+	})
+
+	t.Run("older into newer", func(t *testing.T) {
+		merged := newer
+		merged.Merge(older)
+		checkBars(t, merged.TimeSeries, []OHLCV{
+			bar(date(2023, 9, 5), 1), bar(date(2023, 9, 6), 2), bar(date(2023, 9, 7), 3), bar(date(2023, 9, 8), 4),
+		})
+		if merged.MetaData.LastRefreshed != "2023-09-08" {
+			t.Errorf("LastRefreshed = %q, want the newer metadata", merged.MetaData.LastRefreshed)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var merged TimeSeriesDaily
+		merged.Merge(older)
+		checkBars(t, merged.TimeSeries, older.TimeSeries)
+
+		merged.Merge(TimeSeriesDaily{})
+		checkBars(t, merged.TimeSeries, older.TimeSeries)
+		if merged.MetaData.Symbol != "IBM" {
+			t.Errorf("metadata replaced by that of an empty series: %+v", merged.MetaData)
+		}
+
+		var none TimeSeriesDaily
+		none.Merge(TimeSeriesDaily{})
+		if len(none.TimeSeries) != 0 {
+			t.Errorf("got %d bars from two empty series", len(none.TimeSeries))
+		}
+	})
+}
+
+func TestResample(t *testing.T) {
+	at := func(day, hour, minute int) time.Time { return time.Date(2023, 9, day, hour, minute, 0, 0, time.UTC) }
+	series := TimeSeriesIntraday{
+		MetaData: TimeSeriesMetaData{Symbol: "IBM", Interval: "1min"},
+		TimeSeries: []OHLCV{
+			{Timestamp: at(7, 23, 58), Open: 10, High: 11, Low: 9, Close: 10.5, Volume: 1},
+			{Timestamp: at(7, 23, 59), Open: 10.5, High: 12, Low: 10, Close: 11, Volume: 2},
+			// Midnight opens a new bucket although it is within 15 minutes of the bars above
+			{Timestamp: at(8, 0, 0), Open: 20, High: 21, Low: 19, Close: 20.5, Volume: 3},
+			{Timestamp: at(8, 0, 14), Open: 20.5, High: 22, Low: 18, Close: 21, Volume: 4},
+			{Timestamp: at(8, 0, 15), Open: 30, High: 30, Low: 30, Close: 30, Volume: 5},
+			// Nothing from 00:30 to 09:29: those buckets are omitted, and the last one is partial
+			{Timestamp: at(8, 9, 31), Open: 40, High: 41, Low: 39, Close: 40, Volume: 6},
+		},
+	}
+
+	resampled := series.Resample(15 * time.Minute)
+	checkBars(t, resampled.TimeSeries, []OHLCV{
+		{Timestamp: at(7, 23, 45), Open: 10, High: 12, Low: 9, Close: 11, Volume: 3},
+		{Timestamp: at(8, 0, 0), Open: 20, High: 22, Low: 18, Close: 21, Volume: 7},
+		{Timestamp: at(8, 0, 15), Open: 30, High: 30, Low: 30, Close: 30, Volume: 5},
+		{Timestamp: at(8, 9, 30), Open: 40, High: 41, Low: 39, Close: 40, Volume: 6},
+	})
+	if resampled.MetaData.Interval != "15min" || resampled.MetaData.Symbol != "IBM" {
+		t.Errorf("unexpected metadata %+v", resampled.MetaData)
+	}
+
+	t.Run("non-positive width", func(t *testing.T) {
+		copied := series.Resample(0)
+		checkBars(t, copied.TimeSeries, series.TimeSeries)
+		copied.TimeSeries[0].Close = 0
+		if series.TimeSeries[0].Close == 0 {
+			t.Error("Resample(0) shares the bars of the series")
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if got := (TimeSeriesIntraday{}).Resample(time.Hour); len(got.TimeSeries) != 0 {
+			t.Errorf("got %d bars from an empty series", len(got.TimeSeries))
+		}
+	})
+}
+
+func TestGaps(t *testing.T) {
+	day := 24 * time.Hour
+	series := TimeSeriesDaily{TimeSeries: []OHLCV{
+		bar(date(2023, 8, 31), 1), // Thursday
+		bar(date(2023, 9, 1), 1),  // Friday
+		bar(date(2023, 9, 5), 1),  // Tuesday, after the Labor Day holiday
+		bar(date(2023, 9, 6), 1),
+		bar(date(2023, 9, 8), 1),  // Thursday missing
+		bar(date(2023, 9, 11), 1), // the regular Friday to Monday step
+		bar(date(2023, 9, 25), 1), // a week and a half missing
+	}}
+
+	got := series.Gaps(day)
+	want := [][2]time.Time{
+		{date(2023, 9, 1), date(2023, 9, 5)},
+		{date(2023, 9, 6), date(2023, 9, 8)},
+		{date(2023, 9, 11), date(2023, 9, 25)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Gaps = %v, want %v", got, want)
+	}
+
+	// A looser expectation tolerates the single missing days
+	if got := series.Gaps(2 * day); len(got) != 1 || !got[0][0].Equal(date(2023, 9, 11)) {
+		t.Errorf("Gaps(48h) = %v, want only the gap after 2023-09-11", got)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		if got := (TimeSeriesDaily{}).Gaps(day); len(got) != 0 {
+			t.Errorf("Gaps of an empty series = %v", got)
+		}
+		single := TimeSeriesDaily{TimeSeries: []OHLCV{bar(date(2023, 9, 1), 1)}}
+		if got := single.Gaps(day); len(got) != 0 {
+			t.Errorf("Gaps of a single bar = %v", got)
+		}
+	})
+}
+
+func TestToWeeklyToMonthly(t *testing.T) {
+	ohlcv := func(ts time.Time, open, high, low, close float64, volume int64) OHLCV {
+		return OHLCV{Timestamp: ts, Open: open, High: high, Low: low, Close: close, Volume: volume}
+	}
+	daily := TimeSeriesDaily{
+		MetaData: TimeSeriesMetaData{Symbol: "IBM"},
+		TimeSeries: []OHLCV{
+			ohlcv(date(2023, 9, 28), 1, 2, 0.5, 1.5, 10), // Thursday
+			ohlcv(date(2023, 9, 29), 1.5, 3, 1, 2.5, 20), // Friday, end of month
+			ohlcv(date(2023, 10, 2), 2.5, 2.8, 2, 2.2, 30),
+			ohlcv(date(2023, 10, 3), 2.2, 4, 0.4, 3, 40),
+			// ISO week 1 of 2025 starts on Monday 2024-12-30
+			ohlcv(date(2024, 12, 30), 5, 6, 4, 5.5, 50),
+			ohlcv(date(2025, 1, 2), 5.5, 7, 5, 6, 60),
+		},
+	}
+
+	weekly := daily.ToWeekly()
+	checkBars(t, weekly.TimeSeries, []OHLCV{
+		ohlcv(date(2023, 9, 29), 1, 3, 0.5, 2.5, 30),
+		ohlcv(date(2023, 10, 3), 2.5, 4, 0.4, 3, 70),
+		ohlcv(date(2025, 1, 2), 5, 7, 4, 6, 110),
+	})
+	if weekly.MetaData.Symbol != "IBM" || !strings.HasPrefix(weekly.MetaData.Information, "Weekly") {
+		t.Errorf("unexpected weekly metadata %+v", weekly.MetaData)
+	}
+
+	monthly := daily.ToMonthly()
+	checkBars(t, monthly.TimeSeries, []OHLCV{
+		ohlcv(date(2023, 9, 29), 1, 3, 0.5, 2.5, 30),
+		ohlcv(date(2023, 10, 3), 2.5, 4, 0.4, 3, 70),
+		ohlcv(date(2024, 12, 30), 5, 6, 4, 5.5, 50),
+		ohlcv(date(2025, 1, 2), 5.5, 7, 5, 6, 60),
+	})
+	if !strings.HasPrefix(monthly.MetaData.Information, "Monthly") {
+		t.Errorf("unexpected monthly metadata %+v", monthly.MetaData)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		if got := (TimeSeriesDaily{}).ToWeekly(); len(got.TimeSeries) != 0 {
+			t.Errorf("ToWeekly of an empty series = %v", got.TimeSeries)
+		}
+		if got := (TimeSeriesDaily{}).ToMonthly(); len(got.TimeSeries) != 0 {
+			t.Errorf("ToMonthly of an empty series = %v", got.TimeSeries)
+		}
+	})
+}
+
+func TestDiff(t *testing.T) {
+	older := TimeSeriesDaily{TimeSeries: []OHLCV{
+		bar(date(2023, 9, 5), 1), bar(date(2023, 9, 6), 2), bar(date(2023, 9, 7), 3),
+	}}
+	restated := bar(date(2023, 9, 7), 3)
+	restated.Volume = 150
+	newer := TimeSeriesDaily{TimeSeries: []OHLCV{
+		bar(date(2023, 9, 6), 2), restated, bar(date(2023, 9, 8), 4),
+	}}
+
+	got := older.Diff(newer)
+	want := []BarDiff{
+		{Timestamp: date(2023, 9, 5), Change: BarRemoved, Old: bar(date(2023, 9, 5), 1)},
+		{Timestamp: date(2023, 9, 7), Change: BarChanged, Old: bar(date(2023, 9, 7), 3), New: restated},
+		{Timestamp: date(2023, 9, 8), Change: BarAdded, New: bar(date(2023, 9, 8), 4)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %+v\nwant %+v", got, want)
+	}
+
+	if got := older.Diff(older); len(got) != 0 {
+		t.Errorf("Diff of a series with itself = %+v", got)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		if got := (TimeSeriesDaily{}).Diff(TimeSeriesDaily{}); len(got) != 0 {
+			t.Errorf("Diff of empty series = %+v", got)
+		}
+		added := (TimeSeriesDaily{}).Diff(older)
+		removed := older.Diff(TimeSeriesDaily{})
+		if len(added) != 3 || len(removed) != 3 {
+			t.Fatalf("got %d added and %d removed, want 3 each", len(added), len(removed))
+		}
+		for i := range added {
+			if added[i].Change != BarAdded || removed[i].Change != BarRemoved {
+				t.Errorf("diff %d: %s and %s, want added and removed", i, added[i].Change, removed[i].Change)
+			}
+		}
+	})
+}
+
+func TestAt(t *testing.T) {
+	at := func(minute int) time.Time { return time.Date(2023, 9, 8, 19, minute, 0, 0, time.UTC) }
+	intraday := TimeSeriesIntraday{TimeSeries: []OHLCV{bar(at(45), 1), bar(at(50), 2), bar(at(55), 3)}}
+
+	tests := []struct {
+		name string
+		ts   time.Time
+		want float64
+		ok   bool
+	}{
+		{"first", at(45), 1, true},
+		{"middle", at(50), 2, true},
+		{"last", at(55), 3, true},
+		{"between bars", at(52), 0, false},
+		{"before first", at(40), 0, false},
+		{"after last", at(59), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := intraday.At(tt.ts)
+			if ok != tt.ok || got.Close != tt.want {
+				t.Errorf("At(%v) = %v, %v, want close %v, %v", tt.ts, got.Close, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	t.Run("daily by date", func(t *testing.T) {
+		daily := TimeSeriesDaily{TimeSeries: []OHLCV{bar(date(2023, 9, 6), 1), bar(date(2023, 9, 8), 3)}}
+		// The time of day and zone of the lookup are ignored
+		evening := time.Date(2023, 9, 8, 21, 30, 0, 0, time.FixedZone("EDT", -4*3600))
+		if got, ok := daily.At(evening); !ok || got.Close != 3 {
+			t.Errorf("At(%v) = %v, %v, want the 2023-09-08 bar", evening, got, ok)
+		}
+		if _, ok := daily.At(date(2023, 9, 7)); ok {
+			t.Error("At found a bar for a date between bars")
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, ok := (TimeSeriesIntraday{}).At(at(45)); ok {
+			t.Error("At found a bar in an empty series")
+		}
+	})
+}
+
+func TestLocalize(t *testing.T) {
+	var intraday TimeSeriesIntraday
+	if err := json.Unmarshal(readFixture(t, "intraday.json"), &intraday); err != nil {
+		t.Fatal(err)
+	}
+	if err := intraday.Localize(); err != nil {
+		t.Fatal(err)
+	}
+
+	latest := intraday.TimeSeries[len(intraday.TimeSeries)-1]
+	if latest.Timestamp.Location().String() != "US/Eastern" {
+		t.Errorf("location = %v, want US/Eastern", latest.Timestamp.Location())
+	}
+	// 19:55 in New York on daylight saving time is 23:55 UTC
+	want := time.Date(2023, 9, 8, 23, 55, 0, 0, time.UTC)
+	if !latest.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", latest.Timestamp.UTC(), want)
+	}
+
+	// A second call leaves the localized timestamps alone
+	if err := intraday.Localize(); err != nil {
+		t.Fatal(err)
+	}
+	if got := intraday.TimeSeries[len(intraday.TimeSeries)-1].Timestamp; !got.Equal(want) {
+		t.Errorf("timestamp after a second Localize = %v, want %v", got.UTC(), want)
+	}
+
+	unknown := TimeSeriesIntraday{MetaData: TimeSeriesMetaData{TimeZone: "Mars/Olympus"}}
+	if err := unknown.Localize(); err == nil {
+		t.Error("unknown zone: expected an error")
+	}
+}